@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/litmuschaos/chaos-runner/pkg/log"
+	"github.com/litmuschaos/chaos-runner/pkg/metrics"
 	"github.com/litmuschaos/chaos-runner/pkg/telemetry"
 	"github.com/litmuschaos/chaos-runner/pkg/utils"
 	"github.com/litmuschaos/chaos-runner/pkg/utils/analytics"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func init() {
@@ -32,121 +37,300 @@ func main() {
 			return
 		}
 		defer func() {
-			err = errors.Join(err, shutdown(ctx))
+			// flush the telemetry with a fresh context, as the run context may already be cancelled
+			// ctx is the run context by then, which bounds the flush by the exit deadline once the runner is interrupted
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), utils.ExitTimeout(ctx, 5*time.Second))
+			defer cancel()
+			if err := shutdown(shutdownCtx); err != nil {
+				log.Errorf("unable to shutdown the OTel SDK, error: %v", err)
+			}
 		}()
 		ctx = telemetry.GetTraceParentContext()
 	}
 
+	// Interrupt the run, once the runner pod is asked to terminate
+	ctx, interrupt := utils.NotifyOnTermination(ctx)
+	defer interrupt(nil)
+
 	engineDetails := utils.EngineDetails{}
 	clients := utils.ClientSets{}
 
 	ctx, span := otel.Tracer(telemetry.TracerName).Start(ctx, "ExecuteChaosRunner")
 	defer span.End()
+	// correlate the exported log records with the run
+	telemetry.SetLogContext(ctx)
 
-	// Getting kubeConfig and Generate ClientSets
-	if err := clients.GenerateClientSetFromKubeConfig(); err != nil {
+	// Getting kubeConfig and Generate ClientSets, or load them from the local manifests in offline mode
+	manifestsDir := os.Getenv(utils.OfflineManifestsDirEnv)
+	if manifestsDir != "" {
+		if err := clients.GenerateClientSetFromManifests(manifestsDir); err != nil {
+			log.Errorf("unable to load the manifests, error: %v", err)
+			return
+		}
+	} else if err := clients.GenerateClientSetFromKubeConfig(); err != nil {
 		log.Errorf("unable to create ClientSets, error: %v", err)
 		return
 	}
+	// write the queued events before the chaos-runner exits
+	defer func() { clients.EventRecorder.Shutdown(utils.ExitTimeout(ctx, utils.EventsFlushTimeout)) }()
 	// Fetching all the ENVs passed from the chaos-operator
+	engineDetails.SetEngineDetails()
+	if manifestsDir != "" {
+		// nothing can be launched without a cluster, hence the jobs are only rendered
+		engineDetails.Render.Enabled = true
+		if err := engineDetails.SetEngineDetailsFromManifests(clients); err != nil {
+			log.Errorf("unable to derive the engine details from the manifests, error: %v", err)
+			return
+		}
+	}
 	// create and initialize the experimentList
-	if err := engineDetails.SetEngineDetails().SetEngineUID(clients); err != nil {
+	if err := engineDetails.SetEngineUID(clients); err != nil {
 		log.Errorf("unable to get ChaosEngineUID, error: %v", err)
 		return
 	}
-	experimentList := engineDetails.CreateExperimentList()
+	// the status subresource is decided once, before the experiments patch the chaosengine status in parallel
+	engineDetails.SetStatusSubresource(clients)
+	// derive the engine-level run policies from the chaosengine annotations
+	if err := engineDetails.SetRunPolicyFromEngine(clients); err != nil {
+		log.Errorf("unable to set the run policies, error: %v", err)
+		return
+	}
+	// deliver the notifications in the background, and drain them before the chaos-runner exits
+	engineDetails.StartNotifications(ctx)
+	defer func() { engineDetails.DrainNotifications(utils.ExitTimeout(ctx, utils.NotificationsDrainTimeout)) }()
+	experimentList, err := engineDetails.CreateExperimentList()
+	if err != nil {
+		log.Errorf("unable to resolve the experiment dependencies, error: %v", err)
+		return
+	}
 	log.InfoWithValues("Experiments details are as follows", logrus.Fields{
 		"Experiments List":     engineDetails.Experiments,
 		"Engine Name":          engineDetails.Name,
 		"Targets":              engineDetails.Targets,
 		"Service Account Name": engineDetails.SvcAccount,
 		"Engine Namespace":     engineDetails.EngineNamespace,
+		"Parallelism":          engineDetails.Parallelism,
+		"Dependencies":         engineDetails.Dependencies,
+		"Fail Policy":          engineDetails.FailPolicy,
 	})
 
+	// render the experiment jobs instead of launching them, without patching the chaosengine
+	if engineDetails.Render.Enabled {
+		if err := engineDetails.RenderExperiments(ctx, experimentList, os.Stdout, clients); err != nil {
+			log.Errorf("unable to render the experiment jobs, error: %v", err)
+		}
+		return
+	}
+
+	// serve the metrics of the run, and keep them for the scrapes once the run is completed
+	shutdownMetrics, err := metrics.Init(engineDetails.Name, engineDetails.EngineNamespace)
+	if err != nil {
+		log.Errorf("unable to initialize the metrics, error: %v", err)
+	}
+	defer func() { shutdownMetrics(ctx, utils.ExitTimeout(ctx, metrics.ShutdownTimeout)) }()
+
+	// reconcile with the experiments left behind by a previous chaos-runner pod, if restarted mid-run
+	if err := engineDetails.ResumeExperiments(experimentList, clients); err != nil {
+		log.Errorf("unable to reconcile the existing experiments, error: %v", err)
+		return
+	}
+
 	if err := utils.InitialPatchEngine(engineDetails, clients, experimentList); err != nil {
 		log.Errorf("unable to patch Initial ExperimentStatus in ChaosEngine, error: %v", err)
 		return
 	}
+	engineDetails.Notify(utils.NotificationInitialized, nil, "Chaos Engine: "+engineDetails.Name+" is initialized with Chaos Experiments: "+strings.Join(engineDetails.Experiments, ","))
 
-	// Steps for each Experiment
-	for _, experiment := range experimentList {
+	// Abort the run, once the chaosengine is stopped
+	runCtx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+	go engineDetails.WatchEngineForAbort(runCtx, clients, abort)
 
-		// Sending event to GA instance
-		if engineDetails.ClientUUID != "" {
-			analytics.TriggerAnalytics(experiment.Name, engineDetails.ClientUUID)
+	// Steps for each Experiment, executed by a bounded pool of workers in the order of their dependencies
+	notStarted, haltedBy := engineDetails.RunExperiments(runCtx, experimentList, func(ctx context.Context, experiment *utils.ExperimentDetails) {
+		if experiment.Finished {
+			log.Infof("[skip]: Chaos Experiment: %v is already finished by the previous chaos-runner", experiment.Name)
+			return
 		}
-		// check the existence of chaosexperiment inside the cluster
-		if err := experiment.HandleChaosExperimentExistence(engineDetails, clients); err != nil {
-			log.Errorf("unable to get ChaosExperiment name: %v, in namespace: %v, error: %v", experiment.Name, experiment.Namespace, err)
-			experiment.ExperimentSkipped(utils.ExperimentNotFoundErrorReason, engineDetails, clients)
+		runExperiment(ctx, experiment, engineDetails, clients)
+	}, func(ctx context.Context, experiment *utils.ExperimentDetails, upstreams string) {
+		log.Errorf("skipping Chaos Experiment: %v, as upstream experiment: %v didn't pass", experiment.Name, upstreams)
+		experiment.ExperimentUpstreamFailed(upstreams, engineDetails, clients)
+		engineDetails.ExperimentUpstreamFailedPatchEngine(experiment, clients)
+	})
+
+	// Halt the remaining experiments, if the chaosengine is stopped or the runner is interrupted
+	// or mark them as not run, if an experiment halted the run as per the fail policy
+	cause := context.Cause(runCtx)
+	for _, experiment := range notStarted {
+		if engineDetails.HaltExperiment(cause, experiment, clients) || haltedBy == nil {
 			continue
 		}
-		// derive the required field from the experiment & engine and set into experimentDetails struct
-		if err := experiment.SetValueFromChaosResources(&engineDetails, clients); err != nil {
-			log.Errorf("unable to set values from Chaos Resources, error: %v", err)
-			experiment.ExperimentSkipped(utils.ExperimentNotFoundErrorReason, engineDetails, clients)
-			engineDetails.ExperimentSkippedPatchEngine(&experiment, clients)
-			continue
+		log.Infof("[skip]: not running Chaos Experiment: %v, as experiment: %v halted the run with fail policy: %v", experiment.Name, haltedBy.Name, engineDetails.FailPolicy)
+		experiment.ExperimentNotRun(haltedBy, engineDetails, clients)
+		engineDetails.ExperimentNotRunPatchEngine(experiment, clients)
+	}
+	if errors.Is(cause, utils.ErrRunnerInterrupted) {
+		engineDetails.RunnerInterrupted(clients)
+	}
+	// Record the overall outcome of the run, within the exit deadline once the runner is interrupted
+	exitCtx, cancel := utils.ExitContext(ctx)
+	defer cancel()
+	engineDetails.RecordRunSummary(exitCtx, clients)
+}
+
+// runExperiment executes the complete lifecycle of a single chaos experiment
+func runExperiment(ctx context.Context, experiment *utils.ExperimentDetails, engineDetails utils.EngineDetails, clients utils.ClientSets) {
+	ctx, span := otel.Tracer(telemetry.TracerName).Start(ctx, "RunExperiment", trace.WithAttributes(attribute.String("experiment.name", experiment.Name)))
+	defer func() {
+		span.SetAttributes(experiment.Timings.Attributes()...)
+		span.End()
+	}()
+
+	// Sending event to GA instance
+	if engineDetails.ClientUUID != "" {
+		analytics.TriggerAnalytics(experiment.Name, engineDetails.ClientUUID)
+	}
+	// check the existence of chaosexperiment inside the cluster
+	if err := experiment.HandleChaosExperimentExistence(engineDetails, clients); err != nil {
+		log.WithContext(ctx).Errorf("unable to get ChaosExperiment name: %v, in namespace: %v, error: %v", experiment.Name, experiment.Namespace, err)
+		experiment.ExperimentSkipped(utils.ExperimentNotFoundErrorReason, engineDetails, clients)
+		return
+	}
+	// derive the required field from the experiment & engine and set into experimentDetails struct
+	if err := experiment.SetValueFromChaosResources(&engineDetails, clients); err != nil {
+		log.WithContext(ctx).Errorf("unable to set values from Chaos Resources, error: %v", err)
+		experiment.ExperimentSkipped(utils.ExperimentNotFoundErrorReason, engineDetails, clients)
+		engineDetails.ExperimentSkippedPatchEngine(experiment, clients)
+		return
+	}
+	// derive the envs from the chaos experiment and override their values from chaosengine if any
+	if err := experiment.SetENV(ctx, engineDetails, clients); err != nil {
+		log.WithContext(ctx).Errorf("unable to patch ENV, error: %v", err)
+		experiment.ExperimentSkipped(utils.ExperimentEnvParseErrorReason, engineDetails, clients)
+		engineDetails.ExperimentSkippedPatchEngine(experiment, clients)
+		return
+	}
+	// derive the sidecar details from chaosengine
+	if err := experiment.SetSideCarDetails(engineDetails.Name, clients); err != nil {
+		log.WithContext(ctx).Errorf("unable to get sidecar details, error: %v", err)
+		experiment.ExperimentSkipped(utils.ExperimentSideCarPatchErrorReason, engineDetails, clients)
+		engineDetails.ExperimentSkippedPatchEngine(experiment, clients)
+		return
+	}
+
+	log.WithContext(ctx).Infof("Preparing to run Chaos Experiment: %v", experiment.Name)
+
+	if err := experiment.PatchResources(engineDetails, clients); err != nil {
+		log.WithContext(ctx).Errorf("unable to patch Chaos Resources required for Chaos Experiment: %v, error: %v", experiment.Name, err)
+		experiment.ExperimentSkipped(utils.ExperimentDependencyCheckReason, engineDetails, clients)
+		engineDetails.ExperimentSkippedPatchEngine(experiment, clients)
+		return
+	}
+	// generating experiment dependency check event inside chaosengine
+	experiment.ExperimentDependencyCheck(engineDetails, clients)
+
+	// Don't launch the experiment job, if the chaosengine is stopped or the runner is interrupted meanwhile
+	if engineDetails.HaltExperiment(context.Cause(ctx), experiment, clients) {
+		return
+	}
+
+	// Launch the experiment job, retrying the transient failures according to the retry policy
+	for attempt := experiment.Attempt; ; attempt++ {
+		// the job of an adopted experiment is already launched
+		if !experiment.Adopted {
+			experiment.SetAttempt(attempt)
 		}
-		// derive the envs from the chaos experiment and override their values from chaosengine if any
-		if err := experiment.SetENV(ctx, engineDetails, clients); err != nil {
-			log.Errorf("unable to patch ENV, error: %v", err)
-			experiment.ExperimentSkipped(utils.ExperimentEnvParseErrorReason, engineDetails, clients)
-			engineDetails.ExperimentSkippedPatchEngine(&experiment, clients)
-			continue
+		failureClass, err := launchExperiment(ctx, experiment, engineDetails, clients)
+		experiment.Adopted = false
+		if err == nil {
+			break
 		}
-		// derive the sidecar details from chaosengine
-		if err := experiment.SetSideCarDetails(engineDetails.Name, clients); err != nil {
-			log.Errorf("unable to get sidecar details, error: %v", err)
-			experiment.ExperimentSkipped(utils.ExperimentSideCarPatchErrorReason, engineDetails, clients)
-			engineDetails.ExperimentSkippedPatchEngine(&experiment, clients)
-			continue
+		if engineDetails.HaltExperiment(context.Cause(ctx), experiment, clients) {
+			engineDetails.RecordAttempts(experiment, clients)
+			return
 		}
-
-		log.Infof("Preparing to run Chaos Experiment: %v", experiment.Name)
-
-		if err := experiment.PatchResources(engineDetails, clients); err != nil {
-			log.Errorf("unable to patch Chaos Resources required for Chaos Experiment: %v, error: %v", experiment.Name, err)
-			experiment.ExperimentSkipped(utils.ExperimentDependencyCheckReason, engineDetails, clients)
-			engineDetails.ExperimentSkippedPatchEngine(&experiment, clients)
-			continue
+		if !experiment.RetryPolicy.ShouldRetry(failureClass, attempt) {
+			engineDetails.RecordAttempts(experiment, clients)
+			engineDetails.RecordTermination(experiment, clients)
+			engineDetails.RecordTimings(experiment, clients)
+			if failureClass == utils.DeadlineExceededFailure {
+				engineDetails.TimeoutExperiment(experiment, clients)
+				return
+			}
+			var podFailure *utils.PodFailure
+			if errors.As(err, &podFailure) {
+				engineDetails.FailExperiment(experiment, podFailure, clients)
+				return
+			}
+			reason := utils.ExperimentDependencyCheckReason
+			if failureClass == utils.ChaosContainerWatchFailure {
+				reason = utils.ExperimentChaosContainerWatchErrorReason
+			}
+			experiment.ExperimentSkipped(reason, engineDetails, clients)
+			engineDetails.ExperimentSkippedPatchEngine(experiment, clients)
+			return
 		}
-		// generating experiment dependency check event inside chaosengine
-		experiment.ExperimentDependencyCheck(engineDetails, clients)
-
-		// Creation of PodTemplateSpec, and Final Job
-		if err := utils.BuildingAndLaunchJob(ctx, &experiment, clients); err != nil {
-			log.Errorf("unable to construct chaos experiment job, error: %v", err)
-			experiment.ExperimentSkipped(utils.ExperimentDependencyCheckReason, engineDetails, clients)
-			engineDetails.ExperimentSkippedPatchEngine(&experiment, clients)
-			continue
+		if err := engineDetails.RetryExperiment(ctx, experiment, failureClass, clients); err != nil {
+			engineDetails.HaltExperiment(err, experiment, clients)
+			engineDetails.RecordAttempts(experiment, clients)
+			return
 		}
+	}
+	engineDetails.RecordAttempts(experiment, clients)
 
-		experiment.ExperimentJobCreate(engineDetails, clients)
+	log.WithContext(ctx).Infof("Chaos Pod Completed, Experiment Name: %v, with Job Name: %v", experiment.Name, experiment.JobName)
 
-		log.Infof("Started Chaos Experiment Name: %v, with Job Name: %v", experiment.Name, experiment.JobName)
-		// Watching the chaos container till Completion
-		if err := engineDetails.WatchChaosContainerForCompletion(&experiment, clients); err != nil {
-			log.Errorf("unable to Watch the chaos container, error: %v", err)
-			experiment.ExperimentSkipped(utils.ExperimentChaosContainerWatchErrorReason, engineDetails, clients)
-			engineDetails.ExperimentSkippedPatchEngine(&experiment, clients)
-			continue
+	// Will Update the chaosEngine Status
+	if err := engineDetails.UpdateEngineWithResult(ctx, experiment, clients); err != nil {
+		// the chaosengine is stopped or the runner is interrupted while awaiting the verdict
+		if engineDetails.HaltExperiment(context.Cause(ctx), experiment, clients) {
+			return
 		}
+		log.WithContext(ctx).Errorf("unable to Update ChaosEngine Status, error: %v", err)
+	}
 
-		log.Infof("Chaos Pod Completed, Experiment Name: %v, with Job Name: %v", experiment.Name, experiment.JobName)
+	log.WithContext(ctx).Infof("Chaos Engine has been updated with result, Experiment Name: %v", experiment.Name)
+	// generating experiment completed event, carrying the verdict, inside chaosengine
+	if experiment.Verdict != "" {
+		experiment.ExperimentCompleted(engineDetails, clients)
+	}
 
-		// Will Update the chaosEngine Status
-		if err := engineDetails.UpdateEngineWithResult(&experiment, clients); err != nil {
-			log.Errorf("unable to Update ChaosEngine Status, error: %v", err)
+	// Delete/Retain the Job, based on the jobCleanUpPolicy
+	jobCleanUpPolicy, err := engineDetails.DeleteJobAccordingToJobCleanUpPolicy(context.Background(), experiment, clients)
+	if err != nil {
+		log.WithContext(ctx).Errorf("unable to Delete ChaosExperiment Job, error: %v", err)
+	}
+	experiment.ExperimentJobCleanUp(string(jobCleanUpPolicy), engineDetails, clients)
+}
+
+// launchExperiment launches the experiment job and watches the chaos container till completion
+// It returns the failure class along with the error, if the attempt failed
+func launchExperiment(ctx context.Context, experiment *utils.ExperimentDetails, engineDetails utils.EngineDetails, clients utils.ClientSets) (string, error) {
+	if experiment.Adopted {
+		log.WithContext(ctx).Infof("Resuming Chaos Experiment Name: %v, with Job Name: %v", experiment.Name, experiment.JobName)
+	} else {
+		// Creation of PodTemplateSpec, and Final Job
+		if err := utils.BuildingAndLaunchJob(ctx, experiment, clients); err != nil {
+			log.WithContext(ctx).Errorf("unable to construct chaos experiment job, error: %v", err)
+			return utils.JobCreationFailure, err
 		}
 
-		log.Infof("Chaos Engine has been updated with result, Experiment Name: %v", experiment.Name)
+		experiment.ExperimentJobCreate(engineDetails, clients)
 
-		// Delete/Retain the Job, based on the jobCleanUpPolicy
-		jobCleanUpPolicy, err := engineDetails.DeleteJobAccordingToJobCleanUpPolicy(&experiment, clients)
-		if err != nil {
-			log.Errorf("unable to Delete ChaosExperiment Job, error: %v", err)
+		log.WithContext(ctx).Infof("Started Chaos Experiment Name: %v, with Job Name: %v", experiment.Name, experiment.JobName)
+	}
+	// Watching the chaos container till Completion
+	if err := engineDetails.WatchChaosContainerForCompletion(ctx, experiment, clients); err != nil {
+		if errors.Is(err, utils.ErrDeadlineExceeded) {
+			return utils.DeadlineExceededFailure, err
+		}
+		var podFailure *utils.PodFailure
+		if errors.As(err, &podFailure) {
+			return utils.ChaosPodFailure, err
 		}
-		experiment.ExperimentJobCleanUp(string(jobCleanUpPolicy), engineDetails, clients)
+		log.WithContext(ctx).Errorf("unable to Watch the chaos container, error: %v", err)
+		return utils.ChaosContainerWatchFailure, err
 	}
+	return "", nil
 }
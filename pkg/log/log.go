@@ -1,9 +1,17 @@
 package log
 
 import (
+	"context"
+
 	logrus "github.com/sirupsen/logrus"
 )
 
+// WithContext returns the logger of the given context, so that the exported log records
+// are correlated with the span of the context, e.g. the span of the experiment
+func WithContext(ctx context.Context) *logrus.Entry {
+	return logrus.WithContext(ctx)
+}
+
 // Fatalf Logs first and then calls `logger.Exit(1)`
 // logging level is set to Panic.
 func Fatalf(msg string, err error) {
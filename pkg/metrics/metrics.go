@@ -0,0 +1,233 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+const (
+	// AddressEnv is the ENV holding the address of the /metrics listener, e.g. :8080
+	// The metrics are not served unless it is provided
+	AddressEnv = "METRICS_ADDRESS"
+	// LingerEnv is the ENV holding how long the /metrics listener is kept once the run is completed, e.g. 30s
+	// so that the short-lived chaos-runner pods are still scraped
+	LingerEnv = "METRICS_LINGER"
+	// PushgatewayURLEnv is the ENV holding the url of the Pushgateway, which the metrics are pushed to once the run is completed
+	PushgatewayURLEnv = "METRICS_PUSHGATEWAY_URL"
+	// DefaultLinger is used unless provided with the LingerEnv
+	DefaultLinger = 15 * time.Second
+	// PushgatewayJob is the job the metrics are pushed as, grouped by the chaosengine and its namespace
+	PushgatewayJob = "chaos-runner"
+	// ShutdownTimeout bounds the push of the metrics and the shutdown of the listener, each
+	ShutdownTimeout = 5 * time.Second
+
+	namespace = "litmuschaos_runner"
+)
+
+var (
+	experimentsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "experiments_total",
+		Help:      "Number of the experiments of the run, by verdict",
+	}, []string{"experiment", "verdict"})
+
+	experimentsSkippedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "experiments_skipped_total",
+		Help:      "Number of the skipped experiments, by skip reason",
+	}, []string{"experiment", "reason"})
+
+	experimentDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "experiment_duration_seconds",
+		Help:      "Duration of the experiments, from the launch of their job till their completion",
+		Buckets:   prometheus.ExponentialBuckets(15, 2, 10),
+	}, []string{"experiment"})
+
+	chaosPodSchedulingLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "chaos_pod_scheduling_latency_seconds",
+		Help:      "Latency between the creation and the scheduling of the chaos pods",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 10),
+	}, []string{"experiment"})
+
+	kubeAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "kube_api_request_duration_seconds",
+		Help:      "Latency of the kubernetes API requests, by verb and resource",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"verb", "resource"})
+
+	kubeAPIRequestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kube_api_request_errors_total",
+		Help:      "Number of the failed kubernetes API requests, by verb, resource and status code",
+	}, []string{"verb", "resource", "code"})
+
+	statusPatchConflictsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "status_patch_conflicts_total",
+		Help:      "Number of the chaosengine status patches rejected due to a conflict, which are retried",
+	})
+)
+
+// The Observe functions record the metrics both in prometheus and through the OTel meter provider
+
+// ObserveExperimentVerdict counts the experiment by its verdict
+func ObserveExperimentVerdict(experiment, verdict string) {
+	experimentsTotal.WithLabelValues(experiment, verdict).Inc()
+	otelInstruments.observeExperimentVerdict(experiment, verdict)
+}
+
+// ObserveExperimentSkipped counts the skipped experiment by its skip reason
+func ObserveExperimentSkipped(experiment, reason string) {
+	experimentsSkippedTotal.WithLabelValues(experiment, reason).Inc()
+	otelInstruments.observeExperimentSkipped(experiment, reason)
+}
+
+// ObserveExperimentDuration records the duration of the experiment
+func ObserveExperimentDuration(experiment string, duration time.Duration) {
+	experimentDuration.WithLabelValues(experiment).Observe(duration.Seconds())
+	otelInstruments.observeExperimentDuration(experiment, duration.Seconds())
+}
+
+// ObserveChaosPodScheduling records the scheduling latency of the chaos pod of the experiment
+func ObserveChaosPodScheduling(experiment string, latency time.Duration) {
+	chaosPodSchedulingLatency.WithLabelValues(experiment).Observe(latency.Seconds())
+	otelInstruments.observeChaosPodScheduling(experiment, latency.Seconds())
+}
+
+// ObserveStatusPatchConflict counts the conflicting chaosengine status patch
+func ObserveStatusPatchConflict() {
+	statusPatchConflictsTotal.Inc()
+	otelInstruments.observeStatusPatchConflict()
+}
+
+// NewRegistry returns the registry of the chaos-runner metrics, labelled with the chaosengine and its namespace
+func NewRegistry(engine, engineNamespace string) (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collectors.NewGoCollector()); err != nil {
+		return nil, err
+	}
+	if err := registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, err
+	}
+	if err := registerCollectors(prometheus.WrapRegistererWith(prometheus.Labels{"engine": engine, "namespace": engineNamespace}, registry)); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// newPushRegistry returns the registry of the chaos-runner metrics pushed to the Pushgateway
+// The metrics are unlabelled, as the Pushgateway rejects the labels of the grouping key, i.e. the chaosengine and its namespace
+func newPushRegistry() (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+	if err := registerCollectors(registry); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// registerCollectors registers the chaos-runner metrics
+func registerCollectors(registerer prometheus.Registerer) error {
+	for _, collector := range []prometheus.Collector{
+		experimentsTotal,
+		experimentsSkippedTotal,
+		experimentDuration,
+		chaosPodSchedulingLatency,
+		kubeAPIRequestDuration,
+		kubeAPIRequestErrorsTotal,
+		statusPatchConflictsTotal,
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newHandler serves the metrics of the registry on /metrics
+func newHandler(registry *prometheus.Registry) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return mux
+}
+
+// Init serves the metrics of the run on the /metrics listener and pushes them to the Pushgateway, if configured with the ENVs
+// The OTel measurements are attributed with the chaosengine and its namespace from then on
+// The returned shutdown pushes the metrics and keeps the listener for the linger period, unless the given context is done meanwhile
+// The push and the shutdown of the listener are bounded by the given timeout, each
+func Init(engine, engineNamespace string) (shutdown func(ctx context.Context, timeout time.Duration), err error) {
+	setEngineAttributes(engine, engineNamespace)
+	shutdown = func(context.Context, time.Duration) {}
+	address, pushgatewayURL := os.Getenv(AddressEnv), os.Getenv(PushgatewayURLEnv)
+	if address == "" && pushgatewayURL == "" {
+		return shutdown, nil
+	}
+	registry, err := NewRegistry(engine, engineNamespace)
+	if err != nil {
+		return shutdown, errors.Errorf("unable to register the metrics, error: %v", err)
+	}
+	pushRegistry, err := newPushRegistry()
+	if err != nil {
+		return shutdown, errors.Errorf("unable to register the metrics, error: %v", err)
+	}
+	linger := DefaultLinger
+	if value := os.Getenv(LingerEnv); value != "" {
+		if linger, err = time.ParseDuration(value); err != nil || linger < 0 {
+			return shutdown, errors.Errorf("invalid %v value: %v", LingerEnv, value)
+		}
+	}
+
+	var server *http.Server
+	if address != "" {
+		listener, err := net.Listen("tcp", address)
+		if err != nil {
+			return shutdown, errors.Errorf("unable to listen on %v, error: %v", address, err)
+		}
+		server = &http.Server{Handler: newHandler(registry), ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+				log.Errorf("unable to serve the metrics, error: %v", err)
+			}
+		}()
+		log.Infof("Serving the metrics on %v/metrics", listener.Addr().String())
+	}
+
+	shutdown = func(ctx context.Context, timeout time.Duration) {
+		if pushgatewayURL != "" {
+			pusher := push.New(pushgatewayURL, PushgatewayJob).Gatherer(pushRegistry).Grouping("engine", engine).Grouping("namespace", engineNamespace).
+				Client(&http.Client{Timeout: timeout})
+			if err := pusher.Push(); err != nil {
+				log.Errorf("unable to push the metrics to the Pushgateway, error: %v", err)
+			}
+		}
+		if server == nil {
+			return
+		}
+		if linger > 0 {
+			log.Infof("Keeping the metrics listener for %v, to be scraped", linger)
+			select {
+			case <-ctx.Done():
+			case <-time.After(linger):
+			}
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("unable to shutdown the metrics listener, error: %v", err)
+		}
+	}
+	return shutdown, nil
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestInfo(t *testing.T) {
+	tests := map[string]struct {
+		method           string
+		url              string
+		expectedVerb     string
+		expectedResource string
+	}{
+		"Test Positive-1": {
+			method:           http.MethodGet,
+			url:              "/apis/litmuschaos.io/v1alpha1/namespaces/litmus/chaosengines/engine",
+			expectedVerb:     "get",
+			expectedResource: "chaosengines",
+		},
+		"Test Positive-2": {
+			method:           http.MethodPatch,
+			url:              "/apis/litmuschaos.io/v1alpha1/namespaces/litmus/chaosengines/engine/status",
+			expectedVerb:     "patch",
+			expectedResource: "chaosengines/status",
+		},
+		"Test Positive-3": {
+			method:           http.MethodGet,
+			url:              "/api/v1/namespaces/litmus/pods?labelSelector=job-name%3Dexp&watch=true",
+			expectedVerb:     "watch",
+			expectedResource: "pods",
+		},
+		"Test Positive-4": {
+			method:           http.MethodGet,
+			url:              "/api/v1/namespaces/litmus/pods",
+			expectedVerb:     "list",
+			expectedResource: "pods",
+		},
+		"Test Positive-5": {
+			method:           http.MethodPost,
+			url:              "/apis/batch/v1/namespaces/litmus/jobs",
+			expectedVerb:     "create",
+			expectedResource: "jobs",
+		},
+		"Test Positive-6": {
+			method:           http.MethodGet,
+			url:              "/api/v1/namespaces/litmus",
+			expectedVerb:     "get",
+			expectedResource: "namespaces",
+		},
+		"Test Positive-7": {
+			method:           http.MethodGet,
+			url:              "/apis/events.k8s.io/v1",
+			expectedVerb:     "get",
+			expectedResource: "discovery",
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			request := httptest.NewRequest(mock.method, mock.url, nil)
+			verb, resource := requestInfo(request)
+			if verb != mock.expectedVerb || resource != mock.expectedResource {
+				t.Fatalf("Test %q failed: expected %v of %v, got %v of %v", name, mock.expectedVerb, mock.expectedResource, verb, resource)
+			}
+		})
+	}
+}
+
+func TestInstrumentRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	before := testutil.ToFloat64(kubeAPIRequestErrorsTotal.WithLabelValues("get", "secrets", "404"))
+	client := &http.Client{Transport: InstrumentRoundTripper(http.DefaultTransport)}
+	response, err := client.Get(server.URL + "/api/v1/namespaces/litmus/secrets/missing")
+	if err != nil {
+		t.Fatalf("unable to send the request, err: %v", err)
+	}
+	response.Body.Close()
+
+	if errors := testutil.ToFloat64(kubeAPIRequestErrorsTotal.WithLabelValues("get", "secrets", "404")); errors != before+1 {
+		t.Fatalf("expected the failed request to be counted, got %v", errors-before)
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	registry, err := NewRegistry("fake-engine", "fake-namespace")
+	if err != nil {
+		t.Fatalf("unable to register the metrics, err: %v", err)
+	}
+	ObserveExperimentVerdict("fake-exp-name", "Pass")
+	ObserveExperimentDuration("fake-exp-name", 90*time.Second)
+	server := httptest.NewServer(newHandler(registry))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("unable to scrape the metrics, err: %v", err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("unable to read the metrics, err: %v", err)
+	}
+	for _, expected := range []string{
+		`litmuschaos_runner_experiments_total{engine="fake-engine",experiment="fake-exp-name",namespace="fake-namespace",verdict="Pass"}`,
+		`litmuschaos_runner_experiment_duration_seconds_count{engine="fake-engine",experiment="fake-exp-name",namespace="fake-namespace"}`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("expected the metrics to contain %v, got %v", expected, string(body))
+		}
+	}
+}
+
+func TestInit(t *testing.T) {
+	pushed := make(chan string, 1)
+	pushgateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushed <- r.Method + " " + r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer pushgateway.Close()
+	t.Setenv(AddressEnv, "127.0.0.1:0")
+	t.Setenv(LingerEnv, "0s")
+	t.Setenv(PushgatewayURLEnv, pushgateway.URL)
+
+	shutdown, err := Init("fake-engine", "fake-namespace")
+	if err != nil {
+		t.Fatalf("unable to initialize the metrics, err: %v", err)
+	}
+	shutdown(context.Background(), ShutdownTimeout)
+
+	select {
+	case request := <-pushed:
+		if request != "PUT /metrics/job/"+PushgatewayJob+"/engine/fake-engine/namespace/fake-namespace" {
+			t.Fatalf("expected the metrics to be pushed grouped by the chaosengine, got %v", request)
+		}
+	default:
+		t.Fatalf("expected the metrics to be pushed on shutdown")
+	}
+}
+
+func TestInitWithInvalidLinger(t *testing.T) {
+	t.Setenv(AddressEnv, "127.0.0.1:0")
+	t.Setenv(LingerEnv, "soon")
+	if _, err := Init("fake-engine", "fake-namespace"); err == nil {
+		t.Fatalf("expected the invalid linger to be rejected")
+	}
+}
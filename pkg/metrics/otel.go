@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// MeterName is the name of the meter publishing the chaos-runner metrics through the OTel meter provider
+const MeterName = "litmuschaos.io/chaos-runner"
+
+// instruments are the OTel counterparts of the prometheus metrics
+// They are created from the global meter provider, which delegates them once the OTel SDK is initialized
+type instruments struct {
+	experiments               metric.Int64Counter
+	experimentsSkipped        metric.Int64Counter
+	experimentDuration        metric.Float64Histogram
+	chaosPodSchedulingLatency metric.Float64Histogram
+	kubeAPIRequestDuration    metric.Float64Histogram
+	kubeAPIRequestErrors      metric.Int64Counter
+	statusPatchConflicts      metric.Int64Counter
+}
+
+var (
+	otelInstruments = newInstruments()
+	// engineAttributes contains the chaosengine and its namespace, which all the OTel measurements are attributed with
+	engineAttributes atomic.Value
+)
+
+func newInstruments() *instruments {
+	meter := otel.Meter(MeterName)
+	var errs []error
+	int64Counter := func(name, description string) metric.Int64Counter {
+		counter, err := meter.Int64Counter(name, metric.WithDescription(description))
+		errs = append(errs, err)
+		return counter
+	}
+	float64Histogram := func(name, description string) metric.Float64Histogram {
+		histogram, err := meter.Float64Histogram(name, metric.WithDescription(description), metric.WithUnit("s"))
+		errs = append(errs, err)
+		return histogram
+	}
+	created := &instruments{
+		experiments:               int64Counter("litmuschaos.runner.experiments", "Number of the experiments of the run, by verdict"),
+		experimentsSkipped:        int64Counter("litmuschaos.runner.experiments.skipped", "Number of the skipped experiments, by skip reason"),
+		experimentDuration:        float64Histogram("litmuschaos.runner.experiment.duration", "Duration of the experiments, from the launch of their job till their completion"),
+		chaosPodSchedulingLatency: float64Histogram("litmuschaos.runner.chaos_pod.scheduling_latency", "Latency between the creation and the scheduling of the chaos pods"),
+		kubeAPIRequestDuration:    float64Histogram("litmuschaos.runner.kube_api.request.duration", "Latency of the kubernetes API requests, by verb and resource"),
+		kubeAPIRequestErrors:      int64Counter("litmuschaos.runner.kube_api.request.errors", "Number of the failed kubernetes API requests, by verb, resource and status code"),
+		statusPatchConflicts:      int64Counter("litmuschaos.runner.status_patch.conflicts", "Number of the chaosengine status patches rejected due to a conflict, which are retried"),
+	}
+	for _, err := range errs {
+		if err != nil {
+			log.Errorf("unable to create the OTel instrument, error: %v", err)
+		}
+	}
+	return created
+}
+
+// setEngineAttributes sets the chaosengine and its namespace, which all the OTel measurements are attributed with
+func setEngineAttributes(engine, engineNamespace string) {
+	engineAttributes.Store([]attribute.KeyValue{attribute.String("engine", engine), attribute.String("namespace", engineNamespace)})
+}
+
+// measurementOptions returns the attributes of the OTel measurement, along with the chaosengine and its namespace
+func measurementOptions(attributes ...attribute.KeyValue) metric.MeasurementOption {
+	if engine, ok := engineAttributes.Load().([]attribute.KeyValue); ok {
+		attributes = append(attributes, engine...)
+	}
+	return metric.WithAttributes(attributes...)
+}
+
+func (i *instruments) observeExperimentVerdict(experiment, verdict string) {
+	i.experiments.Add(context.Background(), 1, measurementOptions(attribute.String("experiment", experiment), attribute.String("verdict", verdict)))
+}
+
+func (i *instruments) observeExperimentSkipped(experiment, reason string) {
+	i.experimentsSkipped.Add(context.Background(), 1, measurementOptions(attribute.String("experiment", experiment), attribute.String("reason", reason)))
+}
+
+func (i *instruments) observeExperimentDuration(experiment string, seconds float64) {
+	i.experimentDuration.Record(context.Background(), seconds, measurementOptions(attribute.String("experiment", experiment)))
+}
+
+func (i *instruments) observeChaosPodScheduling(experiment string, seconds float64) {
+	i.chaosPodSchedulingLatency.Record(context.Background(), seconds, measurementOptions(attribute.String("experiment", experiment)))
+}
+
+func (i *instruments) observeKubeAPIRequest(verb, resource string, seconds float64) {
+	i.kubeAPIRequestDuration.Record(context.Background(), seconds, measurementOptions(attribute.String("verb", verb), attribute.String("resource", resource)))
+}
+
+func (i *instruments) observeKubeAPIRequestError(verb, resource, code string) {
+	i.kubeAPIRequestErrors.Add(context.Background(), 1, measurementOptions(attribute.String("verb", verb), attribute.String("resource", resource), attribute.String("code", code)))
+}
+
+func (i *instruments) observeStatusPatchConflict() {
+	i.statusPatchConflicts.Add(context.Background(), 1, measurementOptions())
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// instrumentedRoundTripper records the latency and the errors of the kubernetes API requests
+type instrumentedRoundTripper struct {
+	next http.RoundTripper
+}
+
+// InstrumentRoundTripper wraps the transport of the kubernetes clients, to record the latency and the errors of their requests
+func InstrumentRoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &instrumentedRoundTripper{next: next}
+}
+
+// RoundTrip records the request, as the time till the response headers, which is the time to establish the watches
+func (rt *instrumentedRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	verb, resource := requestInfo(request)
+	start := time.Now()
+	response, err := rt.next.RoundTrip(request)
+	seconds := time.Since(start).Seconds()
+	kubeAPIRequestDuration.WithLabelValues(verb, resource).Observe(seconds)
+	otelInstruments.observeKubeAPIRequest(verb, resource, seconds)
+
+	var code string
+	switch {
+	case err != nil:
+		code = "error"
+	case response.StatusCode >= http.StatusBadRequest:
+		code = strconv.Itoa(response.StatusCode)
+	default:
+		return response, err
+	}
+	kubeAPIRequestErrorsTotal.WithLabelValues(verb, resource, code).Inc()
+	otelInstruments.observeKubeAPIRequestError(verb, resource, code)
+	return response, err
+}
+
+// requestInfo derives the verb and the resource of the kubernetes API request from its method and path, e.g.
+// GET /apis/litmuschaos.io/v1alpha1/namespaces/litmus/chaosengines/engine is a get of chaosengines and
+// PATCH /apis/litmuschaos.io/v1alpha1/namespaces/litmus/chaosengines/engine/status is a patch of chaosengines/status
+func requestInfo(request *http.Request) (string, string) {
+	segments := strings.Split(strings.Trim(request.URL.Path, "/"), "/")
+	var parts []string
+	switch {
+	case len(segments) > 2 && segments[0] == "api":
+		parts = segments[2:]
+	case len(segments) > 3 && segments[0] == "apis":
+		parts = segments[3:]
+	}
+	if len(parts) > 2 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+
+	resource := "discovery"
+	if len(parts) != 0 {
+		resource = parts[0]
+	}
+	if len(parts) > 2 {
+		resource += "/" + parts[2]
+	}
+	isNamed := len(parts) > 1
+
+	switch request.Method {
+	case http.MethodGet:
+		if request.URL.Query().Get("watch") == "true" {
+			return "watch", resource
+		}
+		if isNamed || len(parts) == 0 {
+			return "get", resource
+		}
+		return "list", resource
+	case http.MethodPost:
+		return "create", resource
+	case http.MethodPut:
+		return "update", resource
+	case http.MethodPatch:
+		return "patch", resource
+	case http.MethodDelete:
+		if isNamed {
+			return "delete", resource
+		}
+		return "deletecollection", resource
+	}
+	return strings.ToLower(request.Method), resource
+}
@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	otellog "go.opentelemetry.io/otel/log"
+)
+
+// logContext is the context of the log records without any context of their own,
+// which correlates them with the span of the chaos-runner
+// The log records of the experiments carry the context of their experiment instead, through log.WithContext
+// It is wrapped in the logContextHolder, as the atomic value rejects the contexts of different concrete types
+var logContext atomic.Value
+
+type logContextHolder struct {
+	ctx context.Context
+}
+
+// SetLogContext sets the context, whose span the log records are correlated with
+func SetLogContext(ctx context.Context) {
+	logContext.Store(logContextHolder{ctx: ctx})
+}
+
+// logBridge is the logrus hook, which exports the log records of the chaos-runner through the OTel logger provider
+type logBridge struct {
+	logger otellog.Logger
+}
+
+func newLogBridge(provider otellog.LoggerProvider) *logBridge {
+	return &logBridge{logger: provider.Logger(TracerName)}
+}
+
+// Levels returns the levels of the exported log records
+func (bridge *logBridge) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire exports the log record, along with the trace and span IDs of its context
+func (bridge *logBridge) Fire(entry *logrus.Entry) error {
+	var record otellog.Record
+	record.SetTimestamp(entry.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetBody(otellog.StringValue(entry.Message))
+	record.SetSeverity(severity(entry.Level))
+	record.SetSeverityText(entry.Level.String())
+	for key, value := range entry.Data {
+		record.AddAttributes(otellog.String(key, fmt.Sprint(value)))
+	}
+
+	ctx := entry.Context
+	if holder, ok := logContext.Load().(logContextHolder); ctx == nil && ok {
+		ctx = holder.ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	bridge.logger.Emit(ctx, record)
+	return nil
+}
+
+// severity maps the logrus level to the OTel severity
+func severity(level logrus.Level) otellog.Severity {
+	switch level {
+	case logrus.TraceLevel:
+		return otellog.SeverityTrace
+	case logrus.DebugLevel:
+		return otellog.SeverityDebug
+	case logrus.InfoLevel:
+		return otellog.SeverityInfo
+	case logrus.WarnLevel:
+		return otellog.SeverityWarn
+	case logrus.ErrorLevel:
+		return otellog.SeverityError
+	case logrus.FatalLevel:
+		return otellog.SeverityFatal
+	case logrus.PanicLevel:
+		return otellog.SeverityFatal4
+	}
+	return otellog.SeverityUndefined
+}
@@ -0,0 +1,120 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// recordingExporter keeps the exported log records
+type recordingExporter struct {
+	records []sdklog.Record
+}
+
+func (exporter *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	for _, record := range records {
+		exporter.records = append(exporter.records, record.Clone())
+	}
+	return nil
+}
+
+func (exporter *recordingExporter) Shutdown(context.Context) error { return nil }
+
+func (exporter *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func TestLogBridge(t *testing.T) {
+	ctx, span := sdktrace.NewTracerProvider().Tracer(TracerName).Start(context.Background(), "ExecuteChaosRunner")
+	defer span.End()
+
+	tests := map[string]struct {
+		entryContext context.Context
+		logContext   context.Context
+		level        logrus.Level
+		isCorrelated bool
+	}{
+		"Test Positive-1": {
+			entryContext: ctx,
+			logContext:   context.Background(),
+			level:        logrus.InfoLevel,
+			isCorrelated: true,
+		},
+		"Test Positive-2": {
+			logContext:   ctx,
+			level:        logrus.ErrorLevel,
+			isCorrelated: true,
+		},
+		"Test Negative-1": {
+			logContext:   context.Background(),
+			level:        logrus.WarnLevel,
+			isCorrelated: false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			exporter := &recordingExporter{}
+			provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+			SetLogContext(mock.logContext)
+
+			logger := logrus.New()
+			logger.AddHook(newLogBridge(provider))
+			entry := logger.WithField("experiment", "fake-exp-name")
+			if mock.entryContext != nil {
+				entry = entry.WithContext(mock.entryContext)
+			}
+			entry.Log(mock.level, "fake-message")
+
+			if len(exporter.records) != 1 {
+				t.Fatalf("Test %q failed: expected 1 exported record, got %v", name, len(exporter.records))
+			}
+			record := exporter.records[0]
+			if record.Body().AsString() != "fake-message" || record.Severity() != severity(mock.level) {
+				t.Fatalf("Test %q failed: expected %v record of fake-message, got %v record of %v", name, severity(mock.level), record.Severity(), record.Body().AsString())
+			}
+			var experiment string
+			record.WalkAttributes(func(attr otellog.KeyValue) bool {
+				if attr.Key == "experiment" {
+					experiment = attr.Value.AsString()
+				}
+				return true
+			})
+			if experiment != "fake-exp-name" {
+				t.Fatalf("Test %q failed: expected the record to be attributed with the experiment, got %q", name, experiment)
+			}
+			isCorrelated := record.TraceID() == span.SpanContext().TraceID() && record.SpanID() == span.SpanContext().SpanID()
+			if isCorrelated != mock.isCorrelated {
+				t.Fatalf("Test %q failed: expected the record correlated with the span to be %v, got %v", name, mock.isCorrelated, isCorrelated)
+			}
+		})
+	}
+}
+
+func TestLogBridgeWithExperimentContext(t *testing.T) {
+	tracer := sdktrace.NewTracerProvider().Tracer(TracerName)
+	runCtx, runSpan := tracer.Start(context.Background(), "ExecuteChaosRunner")
+	defer runSpan.End()
+	expCtx, expSpan := tracer.Start(runCtx, "RunExperiment")
+	defer expSpan.End()
+
+	exporter := &recordingExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	SetLogContext(runCtx)
+
+	logger := logrus.New()
+	logger.AddHook(newLogBridge(provider))
+	logger.WithContext(expCtx).Info("fake-experiment-message")
+	logger.Info("fake-run-message")
+
+	if len(exporter.records) != 2 {
+		t.Fatalf("expected 2 exported records, got %v", len(exporter.records))
+	}
+	if record := exporter.records[0]; record.SpanID() != expSpan.SpanContext().SpanID() {
+		t.Fatalf("expected the experiment record to be correlated with the experiment span, got span %v", record.SpanID())
+	}
+	if record := exporter.records[1]; record.SpanID() != runSpan.SpanContext().SpanID() {
+		t.Fatalf("expected the run record to be correlated with the run span, got span %v", record.SpanID())
+	}
+}
@@ -3,12 +3,18 @@ package telemetry
 import (
 	"context"
 	"errors"
+	"os"
 
 	log "github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -19,6 +25,11 @@ import (
 const OTELExporterOTLPEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
 const OTELServiceName = "chaos_runner"
 
+// OTELExporterOTLPLogsEndpoint is the ENV holding the host:port of the OTLP/HTTP receiver of the logs,
+// as the logs are exported over HTTP, unlike the traces and the metrics
+// The logs are exported only if it is provided, as the port of the OTLP/HTTP receiver can't be derived from the OTEL_EXPORTER_OTLP_ENDPOINT
+const OTELExporterOTLPLogsEndpoint = "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"
+
 func InitOTelSDK(ctx context.Context, endpoint string) (shutdown func(context.Context) error, err error) {
 	var shutdownFuncs []func(context.Context) error
 
@@ -35,7 +46,17 @@ func InitOTelSDK(ctx context.Context, endpoint string) (shutdown func(context.Co
 		err = errors.Join(inErr, shutdown(ctx))
 	}
 
-	tracerProvider, err := newTracerProvider(ctx, endpoint)
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(OTELServiceName),
+		),
+	)
+	if err != nil {
+		handleErr(err)
+		return
+	}
+
+	tracerProvider, err := newTracerProvider(ctx, endpoint, res)
 	if err != nil {
 		handleErr(err)
 		return
@@ -47,9 +68,31 @@ func InitOTelSDK(ctx context.Context, endpoint string) (shutdown func(context.Co
 	shutdownFuncs = append(shutdownFuncs, tracerProvider.Shutdown)
 	otel.SetTracerProvider(tracerProvider)
 
-	log.Info("OTel SDK initialized")
+	meterProvider, err := newMeterProvider(ctx, endpoint, res)
+	if err != nil {
+		handleErr(err)
+		return
+	}
+	shutdownFuncs = append(shutdownFuncs, meterProvider.Shutdown)
+	otel.SetMeterProvider(meterProvider)
+
+	logsEndpoint := os.Getenv(OTELExporterOTLPLogsEndpoint)
+	if logsEndpoint == "" {
+		log.Infof("%v is not provided, the logs are not exported", OTELExporterOTLPLogsEndpoint)
+		log.Info("OTel SDK initialized")
+		return
+	}
+	loggerProvider, err := newLoggerProvider(ctx, logsEndpoint, res)
+	if err != nil {
+		handleErr(err)
+		return
+	}
+	shutdownFuncs = append(shutdownFuncs, loggerProvider.Shutdown)
+	global.SetLoggerProvider(loggerProvider)
+	// export the logrus records of pkg/log through the logger provider
+	log.AddHook(newLogBridge(loggerProvider))
 
-	// TODO: need to add metrics & logging provider
+	log.Info("OTel SDK initialized")
 	return
 }
 
@@ -60,12 +103,7 @@ func newPropagator() propagation.TextMapPropagator {
 	)
 }
 
-func newTracerProvider(ctx context.Context, endpoint string) (*trace.TracerProvider, error) {
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(OTELServiceName),
-		),
-	)
+func newTracerProvider(ctx context.Context, endpoint string, res *resource.Resource) (*trace.TracerProvider, error) {
 	traceExporter, err := otlptrace.New(
 		ctx,
 		otlptracegrpc.NewClient(
@@ -87,3 +125,41 @@ func newTracerProvider(ctx context.Context, endpoint string) (*trace.TracerProvi
 
 	return tracerProvider, nil
 }
+
+func newMeterProvider(ctx context.Context, endpoint string, res *resource.Resource) (*sdkmetric.MeterProvider, error) {
+	metricExporter, err := otlpmetricgrpc.New(
+		ctx,
+		// TODO: add secure option
+		otlpmetricgrpc.WithInsecure(),
+		otlpmetricgrpc.WithEndpoint(endpoint),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithResource(res),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+	)
+
+	return meterProvider, nil
+}
+
+func newLoggerProvider(ctx context.Context, endpoint string, res *resource.Resource) (*sdklog.LoggerProvider, error) {
+	logExporter, err := otlploghttp.New(
+		ctx,
+		// TODO: add secure option
+		otlploghttp.WithInsecure(),
+		otlploghttp.WithEndpoint(endpoint),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	loggerProvider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(logExporter)),
+	)
+
+	return loggerProvider, nil
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// ErrEngineStopped is the cause of the cancellation of the run, once the chaosengine is stopped
+var ErrEngineStopped = errors.New("chaosengine is stopped")
+
+// WatchEngineForAbort watches the chaosengine till the context is done,
+// and cancels the run with ErrEngineStopped once its engineState is set to stop
+// The chaosengine is polled instead, if the service account doesn't grant the watch verb on the chaosengines
+func (engineDetails EngineDetails) WatchEngineForAbort(ctx context.Context, clients ClientSets, abort context.CancelCauseFunc) {
+	chaosEngines := clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace)
+	engineWatch := objectWatch{
+		kind:      "ChaosEngine",
+		name:      engineDetails.Name,
+		namespace: engineDetails.EngineNamespace,
+		objType:   &v1alpha1.ChaosEngine{},
+		list: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			return chaosEngines.List(ctx, options)
+		},
+		watch: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return chaosEngines.Watch(ctx, options)
+		},
+		get: func(ctx context.Context) (runtime.Object, error) {
+			return chaosEngines.Get(ctx, engineDetails.Name, metav1.GetOptions{})
+		},
+	}
+	isStopped := waitUntil(ctx, engineWatch, func(obj runtime.Object) bool {
+		engine, isEngine := obj.(*v1alpha1.ChaosEngine)
+		return isEngine && isEngineStopped(engine)
+	})
+	if isStopped {
+		abort(ErrEngineStopped)
+	}
+}
+
+// isEngineStopped checks whether the engineState of the chaosengine is set to stop
+func isEngineStopped(engine *v1alpha1.ChaosEngine) bool {
+	return engine.Spec.EngineState == v1alpha1.EngineStateStop
+}
+
+// AbortExperiment deletes the experiment job, if already launched, and marks the experiment as aborted
+func (engineDetails EngineDetails) AbortExperiment(experiment *ExperimentDetails, clients ClientSets) {
+	log.Infof("ChaosEngine is stopped, aborting Chaos Experiment: %v", experiment.Name)
+	experiment.ExperimentAborted(engineDetails, clients)
+	engineDetails.haltExperiment(context.Background(), experiment, func(expStatus *ExperimentStatus, chaosPodName string) {
+		expStatus.AbortedExperimentStatus(experiment.Name, engineDetails.Name, chaosPodName)
+	}, jobCleanUpAlways, clients)
+}
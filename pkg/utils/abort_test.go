@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	litmusFakeClientset "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned/fake"
+	batchv1 "k8s.io/api/batch/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestWatchEngineForAbort(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+
+	tests := map[string]struct {
+		engineState      v1alpha1.EngineState
+		stopLater        bool
+		isWatchForbidden bool
+		isAborted        bool
+		watchTimeout     time.Duration
+	}{
+		"Test Positive-1": {
+			engineState:  v1alpha1.EngineStateStop,
+			isAborted:    true,
+			watchTimeout: 5 * time.Second,
+		},
+		"Test Positive-2": {
+			engineState:  v1alpha1.EngineStateActive,
+			stopLater:    true,
+			isAborted:    true,
+			watchTimeout: 5 * time.Second,
+		},
+		"Test Positive-3": {
+			engineState:      v1alpha1.EngineStateActive,
+			stopLater:        true,
+			isWatchForbidden: true,
+			isAborted:        true,
+			watchTimeout:     5 * time.Second,
+		},
+		"Test Negative-1": {
+			engineState:  v1alpha1.EngineStateActive,
+			isAborted:    false,
+			watchTimeout: 200 * time.Millisecond,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			if mock.isWatchForbidden {
+				// the service accounts of the experiments don't grant the watch verb on the chaosengines
+				client.LitmusClient.(*litmusFakeClientset.Clientset).PrependWatchReactor("chaosengines", func(action k8stesting.Action) (bool, watch.Interface, error) {
+					return true, nil, k8serrors.NewForbidden(v1alpha1.SchemeGroupVersion.WithResource("chaosengines").GroupResource(), "", errors.New("watch is not granted"))
+				})
+			}
+			// the chaosengine is stopped once it is watched, as the fake watches don't replay the earlier changes
+			watched := notifyOnAction(&client.LitmusClient.(*litmusFakeClientset.Clientset).Fake, "watch", "chaosengines")
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Spec: v1alpha1.ChaosEngineSpec{
+					EngineState: mock.engineState,
+				},
+			}
+			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), mock.watchTimeout)
+			defer cancel()
+			runCtx, abort := context.WithCancelCause(ctx)
+			defer abort(nil)
+
+			done := make(chan struct{})
+			go func() {
+				engineDetails.WatchEngineForAbort(runCtx, client, abort)
+				close(done)
+			}()
+
+			if mock.stopLater {
+				select {
+				case <-watched:
+				case <-time.After(mock.watchTimeout):
+					t.Fatalf("Test %q failed: expected the chaosengine to be watched", name)
+				}
+				chaosEngine.Spec.EngineState = v1alpha1.EngineStateStop
+				if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Update(context.Background(), chaosEngine, metav1.UpdateOptions{}); err != nil {
+					t.Fatalf("engine not updated for %v test, err: %v", name, err)
+				}
+			}
+			<-done
+
+			isAborted := errors.Is(context.Cause(runCtx), ErrEngineStopped)
+			if isAborted != mock.isAborted {
+				t.Fatalf("Test %q failed: expected the run to be aborted: %v, got %v", name, mock.isAborted, isAborted)
+			}
+		})
+	}
+}
+
+func TestAbortExperiment(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	experiment := ExperimentDetails{
+		Name:         "Fake-Exp-Name",
+		Namespace:    "Fake NameSpace",
+		JobName:      "fake-job-name",
+		ChaosPodName: "fake-job-name-abcde",
+	}
+
+	tests := map[string]struct {
+		isJobLaunched bool
+	}{
+		"Test Positive-1": {
+			isJobLaunched: true,
+		},
+		"Test Positive-2": {
+			isJobLaunched: false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Status: v1alpha1.ChaosEngineStatus{
+					Experiments: []v1alpha1.ExperimentStatuses{
+						{
+							Name:   experiment.Name,
+							Status: v1alpha1.ExperimentStatusRunning,
+						},
+					},
+				},
+			}
+			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			if mock.isJobLaunched {
+				job := &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      experiment.JobName,
+						Namespace: experiment.Namespace,
+					},
+				}
+				if _, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("fail to create exp job for %v test, err: %v", name, err)
+				}
+			}
+
+			engineDetails.AbortExperiment(&experiment, client)
+
+			jobList, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil || len(jobList.Items) != 0 {
+				t.Fatalf("Test %q failed: expected the experiment job to be deleted, err: %v", name, err)
+			}
+			chaosEngine, err = client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("fail to get chaosengine after status patch for %v test, err: %v", name, err)
+			}
+			if chaosEngine.Status.Experiments[0].Status != v1alpha1.ExperimentStatusAborted {
+				t.Fatalf("Test %q failed: expected experiment status is %v, got %v", name, v1alpha1.ExperimentStatusAborted, chaosEngine.Status.Experiments[0].Status)
+			}
+			client.EventRecorder.Flush(EventsFlushTimeout)
+			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil || len(events.Items) != 1 || events.Items[0].Reason != ExperimentAbortedReason {
+				t.Fatalf("Test %q failed: expected %v event to be generated, err: %v", name, ExperimentAbortedReason, err)
+			}
+		})
+	}
+}
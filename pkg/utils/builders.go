@@ -3,6 +3,8 @@ package utils
 import (
 	"context"
 	"reflect"
+	"sort"
+	"time"
 
 	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	"github.com/litmuschaos/chaos-runner/pkg/telemetry"
@@ -98,6 +100,10 @@ func getEnvFromMap(m map[string]corev1.EnvVar) []corev1.EnvVar {
 	for _, v := range m {
 		envVars = append(envVars, v)
 	}
+	// keep the order of the envs stable, so that the rendered jobs are reproducible
+	sort.Slice(envVars, func(i, j int) bool {
+		return envVars[i].Name < envVars[j].Name
+	})
 
 	// Add env for getting pod name using downward API
 	envVars = append(envVars, corev1.EnvVar{
@@ -118,13 +124,26 @@ func BuildingAndLaunchJob(ctx context.Context, experiment *ExperimentDetails, cl
 	ctx, span := otel.Tracer(telemetry.TracerName).Start(ctx, "BuildingAndLaunchJob")
 	defer span.End()
 
+	job, err := BuildExperimentJob(experiment)
+	if err != nil {
+		return err
+	}
+	// Creating the Job
+	if err = experiment.launchJob(job, clients); err != nil {
+		return errors.Errorf("unable to launch ChaosExperiment Job, error: %v", err)
+	}
+	return nil
+}
+
+// BuildExperimentJob builds the Job of the experiment, without launching it
+func BuildExperimentJob(experiment *ExperimentDetails) (*batchv1.Job, error) {
 	experiment.VolumeOpts.VolumeOperations(experiment)
 
 	envVars := getEnvFromMap(experiment.envMap)
 	//Build Container to add in the Pod
 	containerForPod, err := buildContainerSpec(experiment, envVars)
 	if err != nil {
-		return errors.Errorf("unable to build Container for Chaos Experiment, error: %v", err)
+		return nil, errors.Errorf("unable to build Container for Chaos Experiment, error: %v", err)
 	}
 
 	containers := []*container.Builder{containerForPod}
@@ -132,7 +151,7 @@ func BuildingAndLaunchJob(ctx context.Context, experiment *ExperimentDetails, cl
 	if len(experiment.SideCars) != 0 {
 		sidecars, err := buildSideCarSpec(experiment)
 		if err != nil {
-			return errors.Errorf("unable to build sidecar Container for Chaos Experiment, error: %v", err)
+			return nil, errors.Errorf("unable to build sidecar Container for Chaos Experiment, error: %v", err)
 		}
 		containers = append(containers, sidecars...)
 	}
@@ -141,29 +160,30 @@ func BuildingAndLaunchJob(ctx context.Context, experiment *ExperimentDetails, cl
 	pod, err := buildPodTemplateSpec(experiment, containers...)
 	if err != nil {
 
-		return errors.Errorf("unable to build PodTemplateSpec for Chaos Experiment, error: %v", err)
+		return nil, errors.Errorf("unable to build PodTemplateSpec for Chaos Experiment, error: %v", err)
 	}
 	// Build JobSpec Template
-	jobspec, err := buildJobSpec(pod)
+	jobspec, err := buildJobSpec(experiment, pod)
 	if err != nil {
-		return errors.Errorf("unable to build JobSpec for Chaos Experiment, error: %v", err)
+		return nil, errors.Errorf("unable to build JobSpec for Chaos Experiment, error: %v", err)
 	}
 	//Build Job
 	job, err := experiment.buildJob(jobspec)
 	if err != nil {
-		return errors.Errorf("unable to Build ChaosExperiment Job, error: %v", err)
+		return nil, errors.Errorf("unable to Build ChaosExperiment Job, error: %v", err)
 	}
-	// Creating the Job
-	if err = experiment.launchJob(job, clients); err != nil {
-		return errors.Errorf("unable to launch ChaosExperiment Job, error: %v", err)
-	}
-	return nil
+	return job, nil
 }
 
 // launchJob spawn a kubernetes Job using the job Object received.
 func (expDetails *ExperimentDetails) launchJob(job *batchv1.Job, clients ClientSets) error {
-	_, err := clients.KubeClient.BatchV1().Jobs(expDetails.Namespace).Create(context.Background(), job, v1.CreateOptions{})
-	return err
+	createdJob, err := clients.KubeClient.BatchV1().Jobs(expDetails.Namespace).Create(context.Background(), job, v1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	expDetails.jobReference = jobReference(createdJob)
+	expDetails.Timings.observeLaunch(time.Now())
+	return nil
 }
 
 // BuildPodTemplateSpec return a PodTemplateSpec
@@ -234,13 +254,17 @@ func setSidecarSecrets(experiment *ExperimentDetails) []v1alpha1.Secret {
 }
 
 // BuildJobSpec returns a JobSpec
-func buildJobSpec(pod *podtemplatespec.Builder) (*jobspec.Builder, error) {
+func buildJobSpec(experiment *ExperimentDetails, pod *podtemplatespec.Builder) (*jobspec.Builder, error) {
 	jobSpecObj := jobspec.NewBuilder().
 		WithPodTemplateSpecBuilder(pod)
-	_, err := jobSpecObj.Build()
+	spec, err := jobSpecObj.Build()
 	if err != nil {
 		return nil, err
 	}
+	// the builder doesn't expose the activeDeadlineSeconds, hence it is set on the underlying spec
+	if experiment.ActiveDeadlineSeconds > 0 {
+		spec.Object.ActiveDeadlineSeconds = &experiment.ActiveDeadlineSeconds
+	}
 	return jobSpecObj, nil
 }
 
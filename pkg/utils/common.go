@@ -3,12 +3,11 @@ package utils
 import (
 	"math/rand"
 	"strings"
-	"time"
 )
 
 // RandomString will generate a random string of length 6
+// It draws from the global source, which is seeded once at startup and safe for the concurrent workers
 func RandomString(length int) string {
-	rand.Seed(time.Now().UnixNano())
 	chars := []rune("abcdefghijklmnopqrstuvwxyz" + "0123456789")
 
 	var b strings.Builder
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRandomStringConcurrently(t *testing.T) {
+	const workers = 64
+	suffixes := make([]string, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			suffixes[i] = RandomString(12)
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for _, suffix := range suffixes {
+		if seen[suffix] {
+			t.Fatalf("expected the concurrent workers to get distinct strings, got %v twice", suffix)
+		}
+		seen[suffix] = true
+	}
+}
@@ -63,7 +63,7 @@ func (expDetails *ExperimentDetails) ValidateConfigMaps(clients ClientSets) erro
 }
 
 func (expDetails *ExperimentDetails) getConfigMapsFromChaosExperiment(clients ClientSets) ([]v1alpha1.ConfigMap, error) {
-	chaosExperimentObj, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(expDetails.Namespace).Get(context.Background(), expDetails.Name, metav1.GetOptions{})
+	chaosExperimentObj, err := expDetails.chaosExperiment(clients)
 	if err != nil {
 		return nil, errors.Errorf("unable to get ChaosExperiment Resource, error: %v", err)
 	}
@@ -73,7 +73,7 @@ func (expDetails *ExperimentDetails) getConfigMapsFromChaosExperiment(clients Cl
 
 func (expDetails *ExperimentDetails) getConfigMapsFromChaosEngine(clients ClientSets, engineDetails EngineDetails) ([]v1alpha1.ConfigMap, error) {
 
-	chaosEngineObj, err := engineDetails.GetChaosEngine(clients)
+	chaosEngineObj, err := expDetails.chaosEngine(engineDetails.Name, clients)
 	if err != nil {
 		return nil, errors.Errorf("unable to get ChaosEngine Resource, error: %v", err)
 	}
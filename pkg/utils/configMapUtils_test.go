@@ -118,6 +118,8 @@ func TestPatchConfigMaps(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			_, err := client.KubeClient.CoreV1().ConfigMaps(experiment.Namespace).Create(context.Background(), &mock.configmap, metav1.CreateOptions{})
 			if err != nil {
@@ -382,6 +384,8 @@ func TestSetConfigMaps(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			if !mock.isErr {
 				_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
@@ -446,6 +450,8 @@ func TestGetConfigMapsFromChaosExperiment(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			if !mock.isErr {
 				_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+const (
+	// MaxDurationAnnotation is the per-experiment chaosengine annotation holding the maximum duration
+	// of the experiment job in seconds, e.g. pod-delete/max-duration: "600"
+	MaxDurationAnnotation = "max-duration"
+	// DeadlineBufferAnnotation is the chaosengine annotation holding the seconds added to the
+	// TOTAL_CHAOS_DURATION and the RAMP_TIME of the experiments to derive their maximum duration
+	DeadlineBufferAnnotation = "runner/deadline-buffer"
+	// DefaultDeadlineBuffer leaves room for the pre & post chaos checks and the probes of the experiment
+	DefaultDeadlineBuffer = 600
+
+	// DeadlineExceededFailure is the failure class of an experiment whose job exceeded its maximum duration
+	DeadlineExceededFailure = "DeadlineExceeded"
+	// jobDeadlineExceededReason is the reason of the failed job condition, once the activeDeadlineSeconds is reached
+	jobDeadlineExceededReason = "DeadlineExceeded"
+)
+
+// ErrDeadlineExceeded is returned while watching the chaos container, once the experiment job exceeded its maximum duration
+var ErrDeadlineExceeded = errors.New("experiment job exceeded its activeDeadlineSeconds")
+
+// SetActiveDeadline derives the maximum duration of the experiment job, either from the one provided
+// in the chaosengine, or from the TOTAL_CHAOS_DURATION of the experiment along with the deadline buffer
+// The RAMP_TIME is counted twice, as the experiments wait for it both before and after the chaos
+// The job is left unbounded if none of them is available
+func (expDetails *ExperimentDetails) SetActiveDeadline(deadlineBuffer int) *ExperimentDetails {
+	if expDetails.MaxDuration > 0 {
+		expDetails.ActiveDeadlineSeconds = int64(expDetails.MaxDuration)
+		return expDetails
+	}
+	chaosDuration, err := strconv.Atoi(expDetails.envMap["TOTAL_CHAOS_DURATION"].Value)
+	if err != nil || chaosDuration <= 0 {
+		log.Warnf("[skip]: unable to derive the maximum duration of Chaos Experiment: %v, as TOTAL_CHAOS_DURATION is not set", expDetails.Name)
+		expDetails.ActiveDeadlineSeconds = 0
+		return expDetails
+	}
+	rampTime, err := strconv.Atoi(expDetails.envMap["RAMP_TIME"].Value)
+	if err != nil || rampTime < 0 {
+		rampTime = 0
+	}
+	expDetails.ActiveDeadlineSeconds = int64(chaosDuration + 2*rampTime + deadlineBuffer)
+	return expDetails
+}
+
+// isJobDeadlineExceeded checks whether the experiment job is failed after exceeding its activeDeadlineSeconds
+func isJobDeadlineExceeded(job *batchv1.Job) bool {
+	if job == nil {
+		return false
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue && condition.Reason == jobDeadlineExceededReason {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeoutExperiment cleans up the experiment job according to the jobCleanUpPolicy and marks the experiment as timed out
+func (engineDetails EngineDetails) TimeoutExperiment(experiment *ExperimentDetails, clients ClientSets) {
+	log.Errorf("Chaos Experiment: %v exceeded its maximum duration of %vs", experiment.Name, experiment.ActiveDeadlineSeconds)
+	experiment.Verdict = string(v1alpha1.ResultVerdictError)
+	experiment.ExperimentTimeout(engineDetails, clients)
+	engineDetails.haltExperiment(context.Background(), experiment, func(expStatus *ExperimentStatus, chaosPodName string) {
+		expStatus.TimedOutExperimentStatus(experiment.Name, engineDetails.Name, chaosPodName)
+	}, jobCleanUpByPolicy, clients)
+}
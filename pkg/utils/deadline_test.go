@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetActiveDeadline(t *testing.T) {
+	tests := map[string]struct {
+		maxDuration      int
+		chaosDuration    string
+		rampTime         string
+		deadlineBuffer   int
+		expectedDeadline int64
+	}{
+		"Test Positive-1": {
+			chaosDuration:    "60",
+			deadlineBuffer:   120,
+			expectedDeadline: 180,
+		},
+		"Test Positive-2": {
+			maxDuration:      300,
+			chaosDuration:    "60",
+			deadlineBuffer:   120,
+			expectedDeadline: 300,
+		},
+		"Test Positive-3": {
+			chaosDuration:    "60",
+			rampTime:         "400",
+			deadlineBuffer:   120,
+			expectedDeadline: 980,
+		},
+		"Test Positive-4": {
+			chaosDuration:    "60",
+			rampTime:         "later",
+			deadlineBuffer:   120,
+			expectedDeadline: 180,
+		},
+		"Test Negative-1": {
+			chaosDuration:    "",
+			deadlineBuffer:   120,
+			expectedDeadline: 0,
+		},
+		"Test Negative-2": {
+			chaosDuration:    "forever",
+			deadlineBuffer:   120,
+			expectedDeadline: 0,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			experiment := ExperimentDetails{
+				Name:        "Fake-Exp-Name",
+				MaxDuration: mock.maxDuration,
+				envMap:      map[string]v1.EnvVar{},
+			}
+			if mock.chaosDuration != "" {
+				experiment.envMap["TOTAL_CHAOS_DURATION"] = v1.EnvVar{Name: "TOTAL_CHAOS_DURATION", Value: mock.chaosDuration}
+			}
+			if mock.rampTime != "" {
+				experiment.envMap["RAMP_TIME"] = v1.EnvVar{Name: "RAMP_TIME", Value: mock.rampTime}
+			}
+			experiment.SetActiveDeadline(mock.deadlineBuffer)
+			if experiment.ActiveDeadlineSeconds != mock.expectedDeadline {
+				t.Fatalf("Test %q failed: expected activeDeadlineSeconds is %v, got %v", name, mock.expectedDeadline, experiment.ActiveDeadlineSeconds)
+			}
+		})
+	}
+}
+
+func TestWatchChaosContainerForDeadline(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	experiment := ExperimentDetails{
+		Name:                  "Fake-Exp-Name",
+		Namespace:             "Fake NameSpace",
+		JobName:               "fake-job-name",
+		StatusCheckTimeout:    2,
+		ActiveDeadlineSeconds: 180,
+	}
+
+	client := CreateFakeClient(t)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      experiment.JobName,
+			Namespace: experiment.Namespace,
+		},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{
+					Type:   batchv1.JobFailed,
+					Status: v1.ConditionTrue,
+					Reason: jobDeadlineExceededReason,
+				},
+			},
+		},
+	}
+	if _, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("fail to create exp job, err: %v", err)
+	}
+
+	err := engineDetails.WatchChaosContainerForCompletion(context.Background(), &experiment, client)
+	if !errors.Is(err, ErrDeadlineExceeded) {
+		t.Fatalf("expected the watch to return %v, got %v", ErrDeadlineExceeded, err)
+	}
+}
+
+func TestTimeoutExperiment(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	experiment := ExperimentDetails{
+		Name:                  "Fake-Exp-Name",
+		Namespace:             "Fake NameSpace",
+		JobName:               "fake-job-name",
+		ActiveDeadlineSeconds: 180,
+	}
+
+	client := CreateFakeClient(t)
+	chaosEngine := &v1alpha1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.Name,
+			Namespace: engineDetails.EngineNamespace,
+		},
+		Spec: v1alpha1.ChaosEngineSpec{
+			JobCleanUpPolicy: v1alpha1.CleanUpPolicyDelete,
+		},
+		Status: v1alpha1.ChaosEngineStatus{
+			Experiments: []v1alpha1.ExperimentStatuses{
+				{
+					Name:   experiment.Name,
+					Status: v1alpha1.ExperimentStatusRunning,
+				},
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("engine not created, err: %v", err)
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      experiment.JobName,
+			Namespace: experiment.Namespace,
+		},
+	}
+	if _, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("fail to create exp job, err: %v", err)
+	}
+
+	engineDetails.TimeoutExperiment(&experiment, client)
+
+	chaosEngine, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("fail to get chaosengine after status patch, err: %v", err)
+	}
+	if chaosEngine.Status.Experiments[0].Status != ExperimentStatusTimedOut {
+		t.Fatalf("expected experiment status is %v, got %v", ExperimentStatusTimedOut, chaosEngine.Status.Experiments[0].Status)
+	}
+	if experiment.Verdict != string(v1alpha1.ResultVerdictError) {
+		t.Fatalf("expected the verdict to be %v, got %v", v1alpha1.ResultVerdictError, experiment.Verdict)
+	}
+	client.EventRecorder.Flush(EventsFlushTimeout)
+	events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("fail to list the events, err: %v", err)
+	}
+	isTimeoutEvent := false
+	for _, event := range events.Items {
+		if event.Reason == ExperimentTimeoutReason {
+			isTimeoutEvent = true
+		}
+	}
+	if !isTimeoutEvent {
+		t.Fatalf("expected %v event to be generated", ExperimentTimeoutReason)
+	}
+	jobList, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(jobList.Items) != 0 {
+		t.Fatalf("expected the experiment job to be deleted, err: %v", err)
+	}
+}
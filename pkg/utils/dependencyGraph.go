@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// validateExperimentDependencies checks that the dependencies between the experiments form a DAG,
+// i.e. every upstream experiment is part of the experiment list and there is no cycle
+func validateExperimentDependencies(experimentList []ExperimentDetails) error {
+	dependencies := make(map[string][]string, len(experimentList))
+	for _, experiment := range experimentList {
+		dependencies[experiment.Name] = experiment.DependsOn
+	}
+
+	for _, experiment := range experimentList {
+		for _, upstream := range experiment.DependsOn {
+			if _, ok := dependencies[upstream]; !ok {
+				return errors.Errorf("experiment: %v depends on %v, which is not part of the experiment list", experiment.Name, upstream)
+			}
+		}
+	}
+
+	// depth first search, where an experiment which is visited again
+	// while its own dependencies are still being visited closes a cycle
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(experimentList))
+	var path []string
+	var visit func(expName string) error
+	visit = func(expName string) error {
+		switch state[expName] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("cyclic dependency between experiments: %v", strings.Join(append(path, expName), " -> "))
+		}
+		state[expName] = visiting
+		path = append(path, expName)
+		for _, upstream := range dependencies[expName] {
+			if err := visit(upstream); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[expName] = visited
+		return nil
+	}
+
+	for _, experiment := range experimentList {
+		if err := visit(experiment.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
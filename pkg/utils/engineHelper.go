@@ -1,14 +1,12 @@
 package utils
 
 import (
-	"context"
 	"fmt"
 	"reflect"
 	"strconv"
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	litmuschaosv1alpha1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 )
@@ -20,7 +18,7 @@ const (
 
 // SetInstanceAttributeValuesFromChaosEngine set the value from the chaosengine
 func (expDetails *ExperimentDetails) SetInstanceAttributeValuesFromChaosEngine(engine *EngineDetails, clients ClientSets) error {
-	chaosEngine, err := engine.GetChaosEngine(clients)
+	chaosEngine, err := expDetails.chaosEngine(engine.Name, clients)
 	if err != nil {
 		return errors.Errorf("unable to get chaosEngine in namespace: %s", engine.EngineNamespace)
 	}
@@ -124,7 +122,7 @@ func (expDetails *ExperimentDetails) SetDefaultHealthCheck(engine *litmuschaosv1
 // SetOverrideEnvFromChaosEngine override the default envs with envs passed inside the chaosengine
 func (expDetails *ExperimentDetails) SetOverrideEnvFromChaosEngine(engineName string, clients ClientSets) error {
 
-	engineSpec, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(expDetails.Namespace).Get(context.Background(), engineName, metav1.GetOptions{})
+	engineSpec, err := expDetails.chaosEngine(engineName, clients)
 	if err != nil {
 		return errors.Errorf("unable to get ChaosEngine Resource in namespace: %v", expDetails.Namespace)
 	}
@@ -166,7 +164,7 @@ func (expDetails *ExperimentDetails) SetOverrideEnvFromChaosEngine(engineName st
 
 func (expDetails *ExperimentDetails) SetSideCarDetails(engineName string, clients ClientSets) error {
 
-	engineSpec, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(expDetails.Namespace).Get(context.Background(), engineName, metav1.GetOptions{})
+	engineSpec, err := expDetails.chaosEngine(engineName, clients)
 	if err != nil {
 		return errors.Errorf("unable to get ChaosEngine Resource in namespace: %v", expDetails.Namespace)
 	}
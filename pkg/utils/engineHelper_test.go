@@ -412,6 +412,8 @@ func TestInstanceAttributeValuesFromChaosEngine(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(mock.chaosengine.Namespace).Create(context.Background(), mock.chaosengine, metav1.CreateOptions{})
 			if err != nil {
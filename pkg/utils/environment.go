@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/litmuschaos/chaos-runner/pkg/log"
 	"github.com/litmuschaos/chaos-runner/pkg/telemetry"
@@ -20,12 +21,15 @@ func (engineDetails *EngineDetails) SetEngineDetails() *EngineDetails {
 	engineDetails.ClientUUID = os.Getenv("CLIENT_UUID")
 	engineDetails.AuxiliaryAppInfo = os.Getenv("AUXILIARY_APPINFO")
 	engineDetails.Targets = os.Getenv("TARGETS")
-	return engineDetails
+	engineDetails.Parallelism = getIntEnv("EXPERIMENT_PARALLELISM", DefaultParallelism)
+	engineDetails.FailPolicy = FailPolicy(os.Getenv("FAIL_POLICY"))
+	engineDetails.StartTime = time.Now()
+	return engineDetails.SetRenderOptions()
 }
 
 // SetEngineUID set the chaosengine UID
 func (engineDetails *EngineDetails) SetEngineUID(clients ClientSets) error {
-	chaosEngine, err := engineDetails.GetChaosEngine(clients)
+	chaosEngine, err := engineDetails.GetChaosEngine(context.Background(), clients)
 	if err != nil {
 		return err
 	}
@@ -51,7 +55,7 @@ func (expDetails *ExperimentDetails) SetENV(ctx context.Context, engineDetails E
 		setEnv("TRACE_PARENT", telemetry.GetMarshalledSpanFromContext(ctx))
 
 	// Get the Default ENV's from ChaosExperiment
-	log.Info("Getting the ENV Variables")
+	log.WithContext(ctx).Info("Getting the ENV Variables")
 	if err := expDetails.SetDefaultEnvFromChaosExperiment(clients); err != nil {
 		return err
 	}
@@ -60,9 +64,29 @@ func (expDetails *ExperimentDetails) SetENV(ctx context.Context, engineDetails E
 	if err := expDetails.SetOverrideEnvFromChaosEngine(engineDetails.Name, clients); err != nil {
 		return err
 	}
+
+	// Deriving the maximum duration of the experiment job from the final ENVs
+	expDetails.SetActiveDeadline(engineDetails.DeadlineBuffer)
 	return nil
 }
 
+// getEnv returns the value of the given env, or the fallback if it is unset
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getIntEnv returns the integer value of the given env, or the fallback if it is unset or invalid
+func getIntEnv(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
 // setEnv set the env inside experimentDetails struct
 func (expDetails *ExperimentDetails) setEnv(key, value string) *ExperimentDetails {
 
@@ -1,66 +1,31 @@
 package utils
 
 import (
-	"context"
+	"strconv"
 	"time"
 
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	"github.com/litmuschaos/chaos-runner/pkg/log"
+	"github.com/litmuschaos/chaos-runner/pkg/metrics"
+	"github.com/pkg/errors"
 	apiv1 "k8s.io/api/core/v1"
-	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
-// CreateEvents create the events in the desired resource
-func (engineDetails EngineDetails) CreateEvents(eventAttributes *EventAttributes, clients ClientSets) error {
-
-	events := &apiv1.Event{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      eventAttributes.Name,
-			Namespace: engineDetails.EngineNamespace,
-		},
-		Source: apiv1.EventSource{
-			Component: engineDetails.Name + "-runner",
-		},
-		Message:        eventAttributes.Message,
-		Reason:         eventAttributes.Reason,
-		Type:           eventAttributes.Type,
-		Count:          1,
-		FirstTimestamp: metav1.Time{Time: time.Now()},
-		LastTimestamp:  metav1.Time{Time: time.Now()},
-		InvolvedObject: apiv1.ObjectReference{
-			APIVersion: "litmuschaos.io/v1alpha1",
-			Kind:       "ChaosEngine",
-			Name:       engineDetails.Name,
-			Namespace:  engineDetails.EngineNamespace,
-			UID:        clientTypes.UID(engineDetails.UID),
-		},
-	}
-
-	_, err := clients.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).Create(context.Background(), events, metav1.CreateOptions{})
-	return err
-
-}
-
-// GenerateEvents update the events and increase the count by 1, if already present
-// else it will create a new event
+// GenerateEvents emits the event regarding the chaosengine, along with the related objects of the event
+// The repeated events are aggregated by the event recorder, instead of being named after their reason
 func (engineDetails EngineDetails) GenerateEvents(eventAttributes *EventAttributes, clients ClientSets) error {
-
-	event, err := clients.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).Get(context.Background(), eventAttributes.Name, metav1.GetOptions{})
-	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			if err := engineDetails.CreateEvents(eventAttributes, clients); err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
-	} else {
-		event.LastTimestamp = metav1.Time{Time: time.Now()}
-		event.Count = event.Count + 1
-		event.Message = eventAttributes.Message
-		_, err = clients.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).Update(context.Background(), event, metav1.UpdateOptions{})
-		return err
+	if clients.EventRecorder == nil {
+		return errors.Errorf("event recorder is not initialised")
+	}
+	engine := engineDetails.objectReference()
+	// the chaosengine event refers to the most specific related object, i.e. the chaos pod once it is known
+	var related *apiv1.ObjectReference
+	if len(eventAttributes.Related) != 0 {
+		related = eventAttributes.Related[len(eventAttributes.Related)-1]
+	}
+	clients.EventRecorder.Eventf(engine, related, eventAttributes.Type, eventAttributes.Reason, eventAttributes.Reason, eventAttributes.Message)
+	for _, object := range eventAttributes.Related {
+		clients.EventRecorder.Eventf(object, engine, eventAttributes.Type, eventAttributes.Reason, eventAttributes.Reason, eventAttributes.Message)
 	}
 	return nil
 }
@@ -70,7 +35,113 @@ func (expDetails ExperimentDetails) ExperimentSkipped(reason string, engineDetai
 	event := EventAttributes{}
 	msg := "Experiment Job creation failed, skipping Chaos Experiment: " + expDetails.Name
 	event.SetEventAttributes(reason, "Warning", msg)
-	event.Name = event.Reason + expDetails.Name + string(engineDetails.UID)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+	engineDetails.Notify(NotificationSkipped, &expDetails, msg)
+	metrics.ObserveExperimentSkipped(expDetails.Name, reason)
+}
+
+// ExperimentUpstreamFailed is an standard event spawned when a ChaosExperiment is skipped
+// because one of the experiments it depends on didn't pass
+func (expDetails ExperimentDetails) ExperimentUpstreamFailed(upstreams string, engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	msg := "Upstream Chaos Experiment: " + upstreams + " didn't pass, skipping Chaos Experiment: " + expDetails.Name
+	event.SetEventAttributes(ExperimentUpstreamFailedReason, "Warning", msg)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+	engineDetails.Notify(NotificationSkipped, &expDetails, msg)
+	metrics.ObserveExperimentSkipped(expDetails.Name, ExperimentUpstreamFailedReason)
+}
+
+// ExperimentAborted is an standard event spawned when a ChaosExperiment is aborted as the ChaosEngine is stopped
+func (expDetails ExperimentDetails) ExperimentAborted(engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	msg := "ChaosEngine is stopped, aborting Chaos Experiment: " + expDetails.Name
+	event.SetEventAttributes(ExperimentAbortedReason, "Warning", msg)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+}
+
+// ExperimentNotRun is an standard event spawned when a ChaosExperiment is not executed as the fail policy halted the run
+func (expDetails ExperimentDetails) ExperimentNotRun(haltedBy *ExperimentDetails, engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	verdict := haltedBy.Verdict
+	if verdict == "" {
+		verdict = string(v1alpha1.ResultVerdictError)
+	}
+	msg := "Chaos Experiment: " + expDetails.Name + " is not run, as experiment: " + haltedBy.Name + " ended with " + verdict +
+		" verdict and the fail policy is set to " + string(engineDetails.FailPolicy)
+	event.SetEventAttributes(ExperimentNotRunReason, "Warning", msg)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+	engineDetails.Notify(NotificationSkipped, &expDetails, msg)
+	metrics.ObserveExperimentSkipped(expDetails.Name, ExperimentNotRunReason)
+}
+
+// ExperimentTimeout is an standard event spawned when a ChaosExperiment job exceeded its maximum duration
+func (expDetails ExperimentDetails) ExperimentTimeout(engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	msg := "Experiment Job " + expDetails.JobName + " for Chaos Experiment: " + expDetails.Name +
+		" exceeded its maximum duration of " + strconv.FormatInt(expDetails.ActiveDeadlineSeconds, 10) + "s"
+	event.SetEventAttributes(ExperimentTimeoutReason, "Warning", msg)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+}
+
+// ExperimentPodFailed is an standard event spawned when the chaos pod of a ChaosExperiment failed for a classified reason
+func (expDetails ExperimentDetails) ExperimentPodFailed(failure *PodFailure, engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	msg := "Chaos Pod " + expDetails.ChaosPodName + " for Chaos Experiment: " + expDetails.Name + " failed with " + failure.Reason
+	if failure.Message != "" {
+		msg += ", " + failure.Message
+	}
+	event.SetEventAttributes(ExperimentPodFailedReason, "Warning", msg)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+}
+
+// ExperimentVerdictTimeout is an standard event spawned when the ChaosResult verdict of a ChaosExperiment is still awaited after the verdict timeout
+func (expDetails ExperimentDetails) ExperimentVerdictTimeout(engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	msg := "ChaosResult verdict of Chaos Experiment: " + expDetails.Name + " is still " + string(v1alpha1.ResultVerdictAwaited) +
+		" after " + strconv.Itoa(engineDetails.VerdictTimeout) + "s"
+	event.SetEventAttributes(ExperimentVerdictTimeoutReason, "Warning", msg)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+}
+
+// ExperimentPodRestarted is an standard event spawned when the chaos pod of a ChaosExperiment is replaced by the job controller
+func (expDetails ExperimentDetails) ExperimentPodRestarted(oldPodName, newPodName string, engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	msg := "Chaos Pod " + oldPodName + " for Chaos Experiment: " + expDetails.Name + " is replaced by " + newPodName
+	event.SetEventAttributes(ExperimentPodRestartedReason, "Warning", msg)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+}
+
+// ExperimentRetried is an standard event spawned when a failed attempt of a ChaosExperiment is retried
+func (expDetails ExperimentDetails) ExperimentRetried(failureClass string, backoff time.Duration, engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	msg := "Attempt " + strconv.Itoa(expDetails.Attempt) + "/" + strconv.Itoa(expDetails.RetryPolicy.MaxAttempts) + " of Chaos Experiment: " + expDetails.Name +
+		" failed with " + failureClass + " failure, retrying in " + backoff.String()
+	event.SetEventAttributes(ExperimentRetriedReason, "Warning", msg)
+	event.Related = expDetails.eventObjects()
 	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
 		log.Errorf("unable to create event, err: %v", err)
 	}
@@ -82,21 +153,26 @@ func (expDetails ExperimentDetails) ExperimentDependencyCheck(engineDetails Engi
 	event := EventAttributes{}
 	msg := "Experiment resources validated for Chaos Experiment: " + expDetails.Name
 	event.SetEventAttributes(ExperimentDependencyCheckReason, "Normal", msg)
-	event.Name = event.Reason + expDetails.Name + string(engineDetails.UID)
+	event.Related = expDetails.eventObjects()
 	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
 		log.Errorf("unable to create event, err: %v", err)
 	}
+	engineDetails.Notify(NotificationDependencyChecked, &expDetails, msg)
 }
 
 // ExperimentJobCreate is an standard event spawned just after starting ChaosExperiment Job
 func (expDetails ExperimentDetails) ExperimentJobCreate(engineDetails EngineDetails, clients ClientSets) {
 	event := EventAttributes{}
 	msg := "Experiment Job " + expDetails.JobName + " for Chaos Experiment: " + expDetails.Name
+	if expDetails.RetryPolicy.MaxAttempts > 1 {
+		msg += ", attempt: " + strconv.Itoa(expDetails.Attempt) + "/" + strconv.Itoa(expDetails.RetryPolicy.MaxAttempts)
+	}
 	event.SetEventAttributes(ExperimentJobCreateReason, "Normal", msg)
-	event.Name = event.Reason + expDetails.Name + string(engineDetails.UID)
+	event.Related = expDetails.eventObjects()
 	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
 		log.Errorf("unable to create event, err: %v", err)
 	}
+	engineDetails.Notify(NotificationJobCreated, &expDetails, msg)
 }
 
 // ExperimentJobCleanUp is an standard event spawned just after deleting ChaosExperiment Job
@@ -106,8 +182,64 @@ func (expDetails ExperimentDetails) ExperimentJobCleanUp(jobCleanUpPolicy string
 	if jobCleanUpPolicy == "delete" {
 		msg = "Experiment Job: " + expDetails.JobName + " will be deleted"
 	}
+	if timings := expDetails.Timings.String(); timings != "" {
+		msg += ", experiment " + timings
+	}
 	event.SetEventAttributes(ExperimentJobCleanUpReason, "Normal", msg)
-	event.Name = event.Reason + expDetails.Name + string(engineDetails.UID)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+	engineDetails.Notify(NotificationCleanedUp, &expDetails, msg)
+}
+
+// ExperimentCompleted is an standard event spawned once the verdict of the ChaosExperiment is updated inside the chaosengine
+// It is a Warning event, unless the ChaosExperiment passed
+func (expDetails ExperimentDetails) ExperimentCompleted(engineDetails EngineDetails, clients ClientSets) {
+	event := EventAttributes{}
+	eventType := "Normal"
+	if expDetails.Verdict != string(v1alpha1.ResultVerdictPassed) {
+		eventType = "Warning"
+	}
+	msg := "Experiment: " + expDetails.Name + " is completed with verdict: " + expDetails.Verdict
+	if expDetails.FailStep != "" {
+		msg += ", fail step: " + expDetails.FailStep
+	}
+	if expDetails.ProbeSuccessPercentage != "" {
+		msg += ", probe success percentage: " + expDetails.ProbeSuccessPercentage
+	}
+	if expDetails.Timings.Duration != "" {
+		msg += ", duration: " + expDetails.Timings.Duration
+	}
+	event.SetEventAttributes(ExperimentCompletedReason, eventType, msg)
+	event.Related = expDetails.eventObjects()
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+	engineDetails.Notify(NotificationCompleted, &expDetails, msg)
+}
+
+// RunnerInterrupted is an standard event spawned when the chaos-runner is terminated
+// before all the ChaosExperiments are finished
+func (engineDetails EngineDetails) RunnerInterrupted(clients ClientSets) {
+	event := EventAttributes{}
+	msg := "Chaos Runner is terminated, remaining Chaos Experiments are interrupted"
+	event.SetEventAttributes(ChaosRunnerInterruptedReason, "Warning", msg)
+	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
+		log.Errorf("unable to create event, err: %v", err)
+	}
+}
+
+// ChaosEngineCompleted is an standard event spawned once all the ChaosExperiments of the run are finished
+// It is a Warning event, unless all the ChaosExperiments passed
+func (engineDetails EngineDetails) ChaosEngineCompleted(summary RunSummary, clients ClientSets) {
+	event := EventAttributes{}
+	eventType := "Normal"
+	if !summary.IsPassed() {
+		eventType = "Warning"
+	}
+	msg := "Chaos Engine: " + engineDetails.Name + " is completed, " + summary.String()
+	event.SetEventAttributes(ChaosEngineCompletedReason, eventType, msg)
 	if err := engineDetails.GenerateEvents(&event, clients); err != nil {
 		log.Errorf("unable to create event, err: %v", err)
 	}
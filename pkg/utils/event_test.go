@@ -6,103 +6,78 @@ import (
 	"testing"
 	"time"
 
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	"github.com/stretchr/testify/require"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
-func TestCreateEvents(t *testing.T) {
-	engineDetails := EngineDetails{
-		Name:            "Fake Engine",
-		EngineNamespace: "Fake NameSpace",
-		UID:             "",
-	}
-
-	eventAtr := EventAttributes{
-		Reason:  "fake-reason",
-		Message: "fake-message",
-		Type:    "fake-type",
-		Name:    "fake-name",
-	}
-	client := CreateFakeClient(t)
-	err := engineDetails.CreateEvents(&eventAtr, client)
-	if err != nil {
-		t.Fatalf("TestCreateEvents failed unable to get event, err: %v", err)
-	}
-
-	events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
-	if err != nil || len(events.Items) == 0 {
-		t.Fatalf("TestCreateEvents failed to get events, err: %v", err)
-	}
-}
-
 func TestGenerateEvents(t *testing.T) {
 	engineDetails := EngineDetails{
 		Name:            "Fake Engine",
 		EngineNamespace: "Fake NameSpace",
-		UID:             "",
+		UID:             "Fake UID",
 	}
-
-	eventAtr := EventAttributes{
-		Reason:  "fake-reason",
-		Message: "fake-message",
-		Type:    "fake-type",
-		Name:    "fake-name",
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fake-job-name",
+			Namespace: engineDetails.EngineNamespace,
+			UID:       "fake-job-uid",
+		},
 	}
 
 	tests := map[string]struct {
-		events v1.Event
-		isErr  bool
+		related        []*v1.ObjectReference
+		times          int
+		expectedEvents map[string]int32
 	}{
 		"Test Positive-1": {
-			isErr: false,
+			times:          1,
+			expectedEvents: map[string]int32{"ChaosEngine": 1},
 		},
 		"Test Positive-2": {
-			events: v1.Event{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      eventAtr.Name,
-					Namespace: engineDetails.EngineNamespace,
-				},
-				Source: v1.EventSource{
-					Component: engineDetails.Name + "-runner",
-				},
-				Message:        eventAtr.Message,
-				Reason:         eventAtr.Reason,
-				Type:           eventAtr.Type,
-				Count:          1,
-				FirstTimestamp: metav1.Time{Time: time.Now()},
-				LastTimestamp:  metav1.Time{Time: time.Now()},
-				InvolvedObject: v1.ObjectReference{
-					APIVersion: "litmuschaos.io/v1alpha1",
-					Kind:       "ChaosEngine",
-					Name:       engineDetails.Name,
-					Namespace:  engineDetails.EngineNamespace,
-					UID:        clientTypes.UID(engineDetails.UID),
-				},
-			},
-			isErr: true,
+			times:          2,
+			expectedEvents: map[string]int32{"ChaosEngine": 2},
+		},
+		"Test Positive-3": {
+			related:        []*v1.ObjectReference{jobReference(job)},
+			times:          1,
+			expectedEvents: map[string]int32{"ChaosEngine": 1, "Job": 1},
 		},
 	}
-
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
-			if mock.isErr {
-				_, err := client.KubeClient.CoreV1().Events(mock.events.Namespace).Create(context.Background(), &mock.events, metav1.CreateOptions{})
-				if err != nil {
-					t.Fatalf("fail to create event for %v test, err: %v", name, err)
-				}
+			eventAtr := EventAttributes{
+				Reason:  "fake-reason",
+				Message: "fake-message",
+				Type:    "Normal",
+				Related: mock.related,
 			}
-			err := engineDetails.GenerateEvents(&eventAtr, client)
-			if err != nil {
-				t.Fatalf("%v fail to generate events, err: %v", name, err)
+			for i := 0; i < mock.times; i++ {
+				if err := engineDetails.GenerateEvents(&eventAtr, client); err != nil {
+					t.Fatalf("%v fail to generate events, err: %v", name, err)
+				}
 			}
+			client.EventRecorder.Flush(EventsFlushTimeout)
 			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
-			if err != nil || len(events.Items) == 0 {
+			if err != nil {
 				t.Fatalf("%v fail to get events, err: %v", name, err)
 			}
+			counts := map[string]int32{}
+			for _, event := range events.Items {
+				if event.Reason != eventAtr.Reason || event.Source.Component != ReportingController {
+					t.Fatalf("Test %q failed: unexpected event %v", name, event)
+				}
+				counts[event.InvolvedObject.Kind] += event.Count
+			}
+			require.Equal(t, mock.expectedEvents, counts)
+			if len(events.Items) != len(mock.expectedEvents) {
+				t.Fatalf("Test %q failed: expected the repeated events to be aggregated, got %v events", name, len(events.Items))
+			}
 		})
 	}
 }
@@ -118,7 +93,6 @@ func TestExperimentSkipped(t *testing.T) {
 		Reason:  "fake-reason",
 		Message: "fake-message",
 		Type:    "fake-type",
-		Name:    "fake-name",
 	}
 	experiment := ExperimentDetails{
 		Name:               "Fake-Exp-Name",
@@ -137,7 +111,7 @@ func TestExperimentSkipped(t *testing.T) {
 		"Test Positive-2": {
 			events: v1.Event{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      eventAtr.Name,
+					Name:      "fake-name",
 					Namespace: engineDetails.EngineNamespace,
 				},
 				Source: v1.EventSource{
@@ -172,12 +146,18 @@ func TestExperimentSkipped(t *testing.T) {
 			}
 			experiment.ExperimentSkipped(eventAtr.Reason, engineDetails, client)
 
+			client.EventRecorder.Flush(EventsFlushTimeout)
+
 			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
 			if err != nil || len(events.Items) == 0 {
 				t.Fatalf("%v fail to get events, err: %v", name, err)
 			}
 
-			if mock.isErr && !strings.Contains(events.Items[1].Message, "Experiment Job creation failed, skipping Chaos Experiment") {
+			skipped := false
+			for _, event := range events.Items {
+				skipped = skipped || strings.Contains(event.Message, "Experiment Job creation failed, skipping Chaos Experiment")
+			}
+			if !skipped {
 				t.Fatalf("%v failed to get the skip event message", name)
 			}
 		})
@@ -216,6 +196,8 @@ func TestExperimentDependencyCheck(t *testing.T) {
 				experiment.ExperimentDependencyCheck(engineDetails, client)
 			}
 
+			client.EventRecorder.Flush(EventsFlushTimeout)
+
 			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
 			if err != nil {
 				t.Fatalf("%v fail to get events, err: %v", name, err)
@@ -261,6 +243,7 @@ func TestExperimentJobCreate(t *testing.T) {
 			if !mock.isErr {
 				experiment.ExperimentJobCreate(engineDetails, client)
 			}
+			client.EventRecorder.Flush(EventsFlushTimeout)
 			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
 			if err != nil {
 				t.Fatalf("%v fail to get events, err: %v", name, err)
@@ -303,6 +286,7 @@ func TestExperimentJobCleanUp(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
 			experiment.ExperimentJobCleanUp(mock.jobCleanupPolicy, engineDetails, client)
+			client.EventRecorder.Flush(EventsFlushTimeout)
 			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
 			if err != nil || len(events.Items) == 0 {
 				t.Fatalf("%v fail to get events, err: %v", name, err)
@@ -317,3 +301,64 @@ func TestExperimentJobCleanUp(t *testing.T) {
 		})
 	}
 }
+
+func TestExperimentCompleted(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+		UID:             "",
+	}
+
+	tests := map[string]struct {
+		chaosResult       *v1alpha1.ChaosResult
+		expectedEventType string
+		expectedMessage   string
+	}{
+		"Test Positive-1": {
+			chaosResult: &v1alpha1.ChaosResult{
+				Status: v1alpha1.ChaosResultStatus{
+					ExperimentStatus: v1alpha1.TestStatus{
+						Verdict:                v1alpha1.ResultVerdictPassed,
+						ProbeSuccessPercentage: "100",
+					},
+				},
+			},
+			expectedEventType: "Normal",
+			expectedMessage:   "Experiment: Fake-Exp-Name is completed with verdict: Pass, probe success percentage: 100, duration: 1m35s",
+		},
+		"Test Positive-2": {
+			chaosResult: &v1alpha1.ChaosResult{
+				Status: v1alpha1.ChaosResultStatus{
+					ExperimentStatus: v1alpha1.TestStatus{
+						Verdict:                v1alpha1.ResultVerdictFailed,
+						ProbeSuccessPercentage: "50",
+						ErrorOutput:            &v1alpha1.ErrorOutput{Reason: "ChaosInject"},
+					},
+				},
+			},
+			expectedEventType: "Warning",
+			expectedMessage:   "Experiment: Fake-Exp-Name is completed with verdict: Fail, fail step: ChaosInject, probe success percentage: 50, duration: 1m35s",
+		},
+	}
+
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			experiment := ExperimentDetails{
+				Name:      "Fake-Exp-Name",
+				Namespace: "Fake NameSpace",
+				Verdict:   string(mock.chaosResult.Status.ExperimentStatus.Verdict),
+				Timings:   ExperimentTimings{Duration: "1m35s"},
+			}
+			client := CreateFakeClient(t)
+			experiment.SetResultDetails(mock.chaosResult).ExperimentCompleted(engineDetails, client)
+			client.EventRecorder.Flush(EventsFlushTimeout)
+			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil || len(events.Items) == 0 {
+				t.Fatalf("%v fail to get events, err: %v", name, err)
+			}
+			require.Equal(t, ExperimentCompletedReason, events.Items[0].Reason)
+			require.Equal(t, mock.expectedEventType, events.Items[0].Type)
+			require.Equal(t, mock.expectedMessage, events.Items[0].Message)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 )
 
@@ -9,7 +11,7 @@ func (engineDetails EngineDetails) ExperimentNotFoundPatchEngine(experiment *Exp
 
 	var expStatus ExperimentStatus
 	expStatus.NotFoundExperimentStatus(experiment.Name, engineDetails.Name)
-	if err := expStatus.PatchChaosEngineStatus(engineDetails, clients); err != nil {
+	if err := expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, clients); err != nil {
 		return errors.Errorf("unable to Patch ChaosEngine with Status, error: %v", err)
 	}
 	return nil
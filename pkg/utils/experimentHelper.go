@@ -1,20 +1,22 @@
 package utils
 
 import (
-	"context"
 	litmuschaosv1alpha1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // CreateExperimentList make the list of all experiment, provided inside chaosengine
-func (engineDetails *EngineDetails) CreateExperimentList() []ExperimentDetails {
+// It also resolves the dependencies between the experiments into a DAG
+func (engineDetails *EngineDetails) CreateExperimentList() ([]ExperimentDetails, error) {
 	var ExperimentDetailsList []ExperimentDetails
 	for i := range engineDetails.Experiments {
 		ExperimentDetailsList = append(ExperimentDetailsList, engineDetails.NewExperimentDetails(i))
 	}
-	return ExperimentDetailsList
+	if err := validateExperimentDependencies(ExperimentDetailsList); err != nil {
+		return nil, err
+	}
+	return ExperimentDetailsList, nil
 }
 
 // NewExperimentDetails create and initialize the experimentDetails
@@ -27,6 +29,13 @@ func (engineDetails *EngineDetails) NewExperimentDetails(i int) ExperimentDetail
 	experimentDetails.Name = engineDetails.Experiments[i]
 	experimentDetails.SvcAccount = engineDetails.SvcAccount
 	experimentDetails.Namespace = engineDetails.EngineNamespace
+	experimentDetails.DependsOn = engineDetails.Dependencies[experimentDetails.Name]
+	experimentDetails.RetryPolicy = DefaultRetryPolicy
+	if policy, ok := engineDetails.RetryPolicies[experimentDetails.Name]; ok {
+		experimentDetails.RetryPolicy = policy
+	}
+	experimentDetails.Attempt = 1
+	experimentDetails.MaxDuration = engineDetails.MaxDurations[experimentDetails.Name]
 	// Setting the JobName in Experiment related struct
 	experimentDetails.JobName = experimentDetails.Name + "-" + RandomString(6)
 	return experimentDetails
@@ -34,9 +43,9 @@ func (engineDetails *EngineDetails) NewExperimentDetails(i int) ExperimentDetail
 
 // SetDefaultEnvFromChaosExperiment sets the Env's in Experiment Structure
 func (expDetails *ExperimentDetails) SetDefaultEnvFromChaosExperiment(clients ClientSets) error {
-	experimentEnv, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(expDetails.Namespace).Get(context.Background(), expDetails.Name, metav1.GetOptions{})
+	experimentEnv, err := expDetails.chaosExperiment(clients)
 	if err != nil {
-		return errors.Errorf("unable to get the %v ChaosExperiment in %v namespace, error: %v", expDetails.Name, expDetails.Namespace, err)
+		return err
 	}
 
 	envList := experimentEnv.Spec.Definition.ENVList
@@ -60,9 +69,10 @@ func (expDetails *ExperimentDetails) SetValueFromChaosResources(engineDetails *E
 }
 
 // HandleChaosExperimentExistence will check the experiment in the app namespace
+// The fetched chaosexperiment is kept inside the snapshot of the experiment
 func (expDetails *ExperimentDetails) HandleChaosExperimentExistence(engineDetails EngineDetails, clients ClientSets) error {
 
-	_, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(expDetails.Namespace).Get(context.Background(), expDetails.Name, metav1.GetOptions{})
+	_, err := expDetails.chaosExperiment(clients)
 	if err != nil {
 		if err := engineDetails.ExperimentNotFoundPatchEngine(expDetails, clients); err != nil {
 			return errors.Errorf("unable to patch Chaos Engine Name: %v, namespace: %v, error: %v", engineDetails.Name, engineDetails.EngineNamespace, err)
@@ -76,9 +86,9 @@ func (expDetails *ExperimentDetails) HandleChaosExperimentExistence(engineDetail
 // SetDefaultAttributeValuesFromChaosExperiment sets value in experimentDetails struct from chaosExperiment
 func (expDetails *ExperimentDetails) SetDefaultAttributeValuesFromChaosExperiment(clients ClientSets, engine *EngineDetails) error {
 
-	experimentSpec, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(expDetails.Namespace).Get(context.Background(), expDetails.Name, metav1.GetOptions{})
+	experimentSpec, err := expDetails.chaosExperiment(clients)
 	if err != nil {
-		return errors.Errorf("unable to get %v ChaosExperiment instance in namespace: %v", expDetails.Name, expDetails.Namespace)
+		return err
 	}
 
 	// fetch all the values from chaosexperiment and set into expDetails struct
@@ -97,6 +107,7 @@ func (expDetails *ExperimentDetails) SetDefaultAttributeValuesFromChaosExperimen
 func (expDetails *ExperimentDetails) SetLabels(experimentSpec *litmuschaosv1alpha1.ChaosExperiment, engine *EngineDetails) *ExperimentDetails {
 	expDetails.ExpLabels = experimentSpec.Spec.Definition.Labels
 	expDetails.ExpLabels["chaosUID"] = engine.UID
+	expDetails.ExpLabels[ExperimentLabel] = expDetails.Name
 	return expDetails
 }
 
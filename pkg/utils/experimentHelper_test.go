@@ -15,6 +15,7 @@ func TestCreateExperimentList(t *testing.T) {
 	tests := map[string]struct {
 		engineDetails EngineDetails
 		isErr         bool
+		isDAGErr      bool
 	}{
 		"Test Positive-1": {
 			engineDetails: EngineDetails{
@@ -27,6 +28,22 @@ func TestCreateExperimentList(t *testing.T) {
 			},
 			isErr: false,
 		},
+		"Test Positive-2": {
+			engineDetails: EngineDetails{
+				Name:            "Fake Engine",
+				EngineNamespace: "Fake NameSpace",
+				Experiments: []string{
+					"fake-exp-1",
+					"fake-exp-2",
+					"fake-exp-3",
+				},
+				Dependencies: map[string][]string{
+					"fake-exp-2": {"fake-exp-1"},
+					"fake-exp-3": {"fake-exp-1", "fake-exp-2"},
+				},
+			},
+			isErr: false,
+		},
 		"Test Negative-1": {
 			engineDetails: EngineDetails{
 				Name:            "Fake Engine",
@@ -34,12 +51,49 @@ func TestCreateExperimentList(t *testing.T) {
 			},
 			isErr: true,
 		},
+		"Test Negative-2": {
+			engineDetails: EngineDetails{
+				Name:            "Fake Engine",
+				EngineNamespace: "Fake NameSpace",
+				Experiments: []string{
+					"fake-exp-1",
+					"fake-exp-2",
+					"fake-exp-3",
+				},
+				Dependencies: map[string][]string{
+					"fake-exp-1": {"fake-exp-3"},
+					"fake-exp-2": {"fake-exp-1"},
+					"fake-exp-3": {"fake-exp-2"},
+				},
+			},
+			isErr:    true,
+			isDAGErr: true,
+		},
+		"Test Negative-3": {
+			engineDetails: EngineDetails{
+				Name:            "Fake Engine",
+				EngineNamespace: "Fake NameSpace",
+				Experiments: []string{
+					"fake-exp-1",
+				},
+				Dependencies: map[string][]string{
+					"fake-exp-1": {"fake-exp-unknown"},
+				},
+			},
+			isErr:    true,
+			isDAGErr: true,
+		},
 	}
 
 	for name, moke := range tests {
 		t.Run(name, func(t *testing.T) {
 
-			ExpList := moke.engineDetails.CreateExperimentList()
+			ExpList, err := moke.engineDetails.CreateExperimentList()
+			if moke.isDAGErr && err == nil {
+				t.Fatalf("%v test failed as the invalid experiment dependencies are resolved", name)
+			} else if !moke.isDAGErr && err != nil {
+				t.Fatalf("%v test failed to resolve the experiment dependencies, err: %v", name, err)
+			}
 			if len(ExpList) == 0 && !moke.isErr {
 				t.Fatalf("%v test failed as the experiment list is still empty", name)
 			} else if len(ExpList) != 0 && moke.isErr {
@@ -142,6 +196,8 @@ func TestSetDefaultEnvFromChaosExperiment(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
 			if err != nil {
@@ -257,6 +313,8 @@ func TestSetDefaultAttributeValuesFromChaosExperiment(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
 			if err != nil {
@@ -388,6 +446,8 @@ func TestSetValueFromChaosResources(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
 			if err != nil {
@@ -868,6 +928,8 @@ func TestHandleChaosExperimentExistence(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			if !mock.isErr {
 				_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
@@ -77,6 +77,8 @@ func TestPatchHostFileVolumes(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
 			if err != nil {
@@ -2,33 +2,51 @@ package utils
 
 import (
 	"context"
+
 	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	"github.com/litmuschaos/chaos-runner/pkg/log"
 	"github.com/pkg/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ExperimentStatus is wrapper for v1alpha1.ExperimentStatuses
 type ExperimentStatus v1alpha1.ExperimentStatuses
 
 // InitialPatchEngine patches the chaosEngine with the initial ExperimentStatuses
+// The status of the experiments finished or adopted after a restart of the chaos-runner is retained,
+// and the existing status of the other experiments is reset, instead of being appended again
 func InitialPatchEngine(engineDetails EngineDetails, clients ClientSets, experimentList []ExperimentDetails) error {
+	err := retryOnStatusPatchFailure(func() error {
+		// Get chaosengine Object
+		expEngine, err := engineDetails.GetChaosEngine(context.Background(), clients)
+		if err != nil {
+			return errors.Errorf("unable to get ChaosEngine, error: %v", err)
+		}
 
-	// Get chaosengine Object
-	expEngine, err := engineDetails.GetChaosEngine(clients)
+		// patch the experiment status in chaosengine
+		var operations []jsonPatchOperation
+		if expEngine.Status.Experiments == nil {
+			operations = append(operations, jsonPatchOperation{Op: "add", Path: "/status/experiments", Value: []v1alpha1.ExperimentStatuses{}})
+		}
+		for _, v := range experimentList {
+			experimentIndex := checkStatusListForExp(expEngine.Status.Experiments, v.Name)
+			if experimentIndex != -1 && (v.Finished || v.Adopted) {
+				continue
+			}
+			var expStatus ExperimentStatus
+			expStatus.InitialExperimentStatus(v.Name, engineDetails.Name)
+			if experimentIndex != -1 {
+				operations = append(operations, replaceExperimentStatusPatch(experimentIndex, v1alpha1.ExperimentStatuses(expStatus))...)
+				continue
+			}
+			operations = append(operations, jsonPatchOperation{Op: "add", Path: "/status/experiments/-", Value: v1alpha1.ExperimentStatuses(expStatus)})
+		}
+		if len(operations) == 0 {
+			return nil
+		}
+		return engineDetails.patchChaosEngineStatus(context.Background(), operations, clients)
+	})
 	if err != nil {
-		return errors.Errorf("unable to get ChaosEngine, error: %v", err)
-	}
-
-	// patch the experiment status in chaosengine
-	for _, v := range experimentList {
-		var expStatus ExperimentStatus
-		expStatus.InitialExperimentStatus(v.Name, engineDetails.Name)
-		expEngine.Status.Experiments = append(expEngine.Status.Experiments, v1alpha1.ExperimentStatuses(expStatus))
-	}
-	_, updateErr := clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Update(context.Background(), expEngine, metav1.UpdateOptions{})
-	if updateErr != nil {
-		return errors.Errorf("unable to update ChaosEngine in namespace: %v, error: %v", engineDetails.EngineNamespace, updateErr)
+		return errors.Errorf("unable to update ChaosEngine in namespace: %v, error: %v", engineDetails.EngineNamespace, err)
 	}
 	return nil
 }
@@ -37,7 +55,25 @@ func InitialPatchEngine(engineDetails EngineDetails, clients ClientSets, experim
 func (engineDetails EngineDetails) ExperimentSkippedPatchEngine(experiment *ExperimentDetails, clients ClientSets) {
 	var expStatus ExperimentStatus
 	expStatus.SkippedExperimentStatus(experiment.Name, engineDetails.Name)
-	if err := expStatus.PatchChaosEngineStatus(engineDetails, clients); err != nil {
+	if err := expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, clients); err != nil {
+		log.Errorf("unable to Patch ChaosEngine with Status, error: %v", err)
+	}
+}
+
+// ExperimentUpstreamFailedPatchEngine patches the chaosEngine with skipped status, when an upstream experiment didn't pass
+func (engineDetails EngineDetails) ExperimentUpstreamFailedPatchEngine(experiment *ExperimentDetails, clients ClientSets) {
+	var expStatus ExperimentStatus
+	expStatus.UpstreamFailedExperimentStatus(experiment.Name, engineDetails.Name)
+	if err := expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, clients); err != nil {
+		log.Errorf("unable to Patch ChaosEngine with Status, error: %v", err)
+	}
+}
+
+// ExperimentNotRunPatchEngine patches the chaosEngine with not-run status, when the fail policy halted the run
+func (engineDetails EngineDetails) ExperimentNotRunPatchEngine(experiment *ExperimentDetails, clients ClientSets) {
+	var expStatus ExperimentStatus
+	expStatus.NotRunExperimentStatus(experiment.Name, engineDetails.Name)
+	if err := expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, clients); err != nil {
 		log.Errorf("unable to Patch ChaosEngine with Status, error: %v", err)
 	}
 }
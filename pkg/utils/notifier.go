@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	litmuschaosv1alpha1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// NotifierSecretAnnotation is the chaosengine annotation holding the name of the secret, inside the chaosengine namespace,
+// which configures the webhook notified at each lifecycle point of the run, e.g. runner/notifier-secret: chaos-webhook
+// The secret contains the url of the webhook, along with the optional signingKey, retries and timeout keys
+const NotifierSecretAnnotation = "runner/notifier-secret"
+
+const (
+	// NotificationsDrainTimeout bounds the delivery of the queued notifications, once the run is completed
+	NotificationsDrainTimeout = 10 * time.Second
+	// notificationQueueSize bounds the notifications waiting for their delivery, the further ones are dropped
+	notificationQueueSize = 64
+)
+
+// NotificationType is the lifecycle point of the run, which the notification is sent at
+// It is used as the type of the CloudEvents sent to the webhook
+type NotificationType string
+
+const (
+	// NotificationInitialized is sent once the initial experiment statuses are patched inside the chaosengine
+	NotificationInitialized NotificationType = "io.litmuschaos.runner.engine.initialized"
+	// NotificationDependencyChecked is sent once the resources of the experiment are validated
+	NotificationDependencyChecked NotificationType = "io.litmuschaos.runner.experiment.dependency-checked"
+	// NotificationJobCreated is sent once the experiment job is created
+	NotificationJobCreated NotificationType = "io.litmuschaos.runner.experiment.job-created"
+	// NotificationPodRunning is sent once the chaos pod of the experiment is running, i.e. the chaos is started
+	NotificationPodRunning NotificationType = "io.litmuschaos.runner.experiment.pod-running"
+	// NotificationCompleted is sent once the verdict of the experiment is updated inside the chaosengine
+	NotificationCompleted NotificationType = "io.litmuschaos.runner.experiment.completed"
+	// NotificationSkipped is sent once the experiment is skipped
+	NotificationSkipped NotificationType = "io.litmuschaos.runner.experiment.skipped"
+	// NotificationCleanedUp is sent once the experiment job is deleted or retained, as per the jobCleanUpPolicy
+	NotificationCleanedUp NotificationType = "io.litmuschaos.runner.experiment.cleaned-up"
+)
+
+// Notification contains the details of the lifecycle point of the run
+type Notification struct {
+	Type                   NotificationType `json:"-"`
+	Time                   metav1.Time      `json:"-"`
+	Engine                 string           `json:"engine"`
+	Namespace              string           `json:"namespace"`
+	EngineUID              string           `json:"engineUID,omitempty"`
+	Experiment             string           `json:"experiment,omitempty"`
+	JobName                string           `json:"jobName,omitempty"`
+	ChaosPodName           string           `json:"chaosPodName,omitempty"`
+	Attempt                int              `json:"attempt,omitempty"`
+	Verdict                string           `json:"verdict,omitempty"`
+	FailStep               string           `json:"failStep,omitempty"`
+	ProbeSuccessPercentage string           `json:"probeSuccessPercentage,omitempty"`
+	Message                string           `json:"message,omitempty"`
+}
+
+// Notifier is notified at each lifecycle point of the run
+// The lifecycle points of the experiments are notified along with their events
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// Notify queues the notification of the given lifecycle point for all the notifiers of the run
+// The experiment is optional, as some lifecycle points belong to the chaosengine
+// The notifications are delivered in the background, and the failed ones are only logged, they never halt the run
+func (engineDetails EngineDetails) Notify(notificationType NotificationType, expDetails *ExperimentDetails, msg string) {
+	if engineDetails.notifications == nil {
+		return
+	}
+	notification := Notification{
+		Type:      notificationType,
+		Time:      metav1.Now(),
+		Engine:    engineDetails.Name,
+		Namespace: engineDetails.EngineNamespace,
+		EngineUID: engineDetails.UID,
+		Message:   msg,
+	}
+	if expDetails != nil {
+		notification.Experiment = expDetails.Name
+		notification.JobName = expDetails.JobName
+		notification.ChaosPodName = expDetails.ChaosPodName
+		notification.Attempt = expDetails.Attempt
+		notification.Verdict = expDetails.Verdict
+		notification.FailStep = expDetails.FailStep
+		notification.ProbeSuccessPercentage = expDetails.ProbeSuccessPercentage
+	}
+	engineDetails.notifications.enqueue(notification)
+}
+
+// StartNotifications starts delivering the notifications of the run to its notifiers, within the given run context
+// The deliveries outlive the cancellation of the run context till the exit deadline,
+// as the clean up of the interrupted run is notified as well
+func (engineDetails *EngineDetails) StartNotifications(ctx context.Context) {
+	if len(engineDetails.Notifiers) == 0 {
+		return
+	}
+	engineDetails.notifications = newNotificationQueue(ctx, engineDetails.Notifiers)
+}
+
+// DrainNotifications delivers the queued notifications, bounded by the given timeout,
+// and drops the remaining ones once the timeout is reached
+func (engineDetails EngineDetails) DrainNotifications(timeout time.Duration) {
+	if engineDetails.notifications == nil {
+		return
+	}
+	engineDetails.notifications.drain(timeout)
+}
+
+// notificationQueue delivers the notifications to the notifiers in order, through a single background worker,
+// so that an unreachable notifier never blocks the run. It is bounded, and drops the notifications once full
+type notificationQueue struct {
+	notifiers []Notifier
+	// mu guards the queue against the notifications sent after it is drained
+	mu     sync.Mutex
+	closed bool
+	queue  chan Notification
+	done   chan struct{}
+	// ctx is the context of the deliveries, it is cancelled once the queue is drained
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newNotificationQueue(runCtx context.Context, notifiers []Notifier) *notificationQueue {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(runCtx))
+	queue := &notificationQueue{
+		notifiers: notifiers,
+		queue:     make(chan Notification, notificationQueueSize),
+		done:      make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	go queue.run(runCtx)
+	return queue
+}
+
+// run delivers the queued notifications till the queue is drained
+// The deliveries are cancelled at the exit deadline, once the run context is cancelled
+func (queue *notificationQueue) run(runCtx context.Context) {
+	go func() {
+		select {
+		case <-runCtx.Done():
+		case <-queue.done:
+			return
+		}
+		exitCtx, cancel := ExitContext(runCtx)
+		defer cancel()
+		select {
+		case <-exitCtx.Done():
+			queue.cancel()
+		case <-queue.done:
+		}
+	}()
+
+	defer close(queue.done)
+	for notification := range queue.queue {
+		if queue.ctx.Err() != nil {
+			log.Warnf("[skip]: dropping %v notification, as the notifications are cancelled", notification.Type)
+			continue
+		}
+		for _, notifier := range queue.notifiers {
+			if err := notifier.Notify(queue.ctx, notification); err != nil {
+				log.Errorf("unable to send the %v notification, error: %v", notification.Type, err)
+			}
+		}
+	}
+}
+
+// enqueue queues the notification without blocking, it is dropped if the queue is full or already drained
+func (queue *notificationQueue) enqueue(notification Notification) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if queue.closed {
+		log.Warnf("[skip]: dropping %v notification, as the notifications are already drained", notification.Type)
+		return
+	}
+	select {
+	case queue.queue <- notification:
+	default:
+		log.Warnf("[skip]: dropping %v notification, as %v notifications are already queued", notification.Type, notificationQueueSize)
+	}
+}
+
+// drain waits till the queued notifications are delivered, bounded by the given timeout,
+// and cancels the remaining deliveries once the timeout is reached
+func (queue *notificationQueue) drain(timeout time.Duration) {
+	queue.mu.Lock()
+	if !queue.closed {
+		queue.closed = true
+		close(queue.queue)
+	}
+	queue.mu.Unlock()
+
+	select {
+	case <-queue.done:
+	case <-time.After(timeout):
+		log.Warnf("unable to deliver the queued notifications within %v, dropping the remaining ones", timeout)
+		queue.cancel()
+		<-queue.done
+	}
+	queue.cancel()
+}
+
+// SetNotifiersFromEngine sets the notifiers of the run from the secret provided in the chaosengine annotations
+// The run continues without notifications if the secret is invalid
+func (engineDetails *EngineDetails) SetNotifiersFromEngine(engine *litmuschaosv1alpha1.ChaosEngine, clients ClientSets) *EngineDetails {
+	secretName, ok := engine.Annotations[NotifierSecretAnnotation]
+	if !ok || engineDetails.Render.Enabled {
+		return engineDetails
+	}
+	config, err := getWebhookConfig(secretName, engineDetails.EngineNamespace, clients)
+	if err != nil {
+		log.Warnf("[skip]: invalid %v annotation value: %v, error: %v", NotifierSecretAnnotation, secretName, err)
+		return engineDetails
+	}
+	engineDetails.Notifiers = append(engineDetails.Notifiers, NewWebhookNotifier(config))
+	return engineDetails
+}
+
+// getWebhookConfig derives the webhook configuration from the given secret
+func getWebhookConfig(secretName, namespace string, clients ClientSets) (WebhookConfig, error) {
+	secret, err := clients.KubeClient.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return WebhookConfig{}, errors.Errorf("unable to get the secret, error: %v", err)
+	}
+	config := WebhookConfig{
+		URL:        string(secret.Data[WebhookURLKey]),
+		SigningKey: secret.Data[WebhookSigningKey],
+		Retries:    DefaultWebhookRetries,
+		Timeout:    DefaultWebhookTimeout,
+	}
+	if config.URL == "" {
+		return WebhookConfig{}, errors.Errorf("the %v key is missing", WebhookURLKey)
+	}
+	if value, ok := secret.Data[WebhookRetriesKey]; ok {
+		retries, err := strconv.Atoi(string(value))
+		if err != nil || retries < 0 {
+			return WebhookConfig{}, errors.Errorf("invalid %v value: %v", WebhookRetriesKey, string(value))
+		}
+		config.Retries = retries
+	}
+	if value, ok := secret.Data[WebhookTimeoutKey]; ok {
+		timeout, err := time.ParseDuration(string(value))
+		if err != nil || timeout <= 0 {
+			return WebhookConfig{}, errors.Errorf("invalid %v value: %v", WebhookTimeoutKey, string(value))
+		}
+		config.Timeout = timeout
+	}
+	return config, nil
+}
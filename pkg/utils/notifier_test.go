@@ -0,0 +1,207 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// blockingNotifier blocks each delivery till its context is done, as an unreachable webhook
+type blockingNotifier struct {
+	cancelled chan struct{}
+}
+
+func (notifier *blockingNotifier) Notify(ctx context.Context, notification Notification) error {
+	<-ctx.Done()
+	select {
+	case notifier.cancelled <- struct{}{}:
+	default:
+	}
+	return ctx.Err()
+}
+
+// fakeNotifier records the received notifications
+type fakeNotifier struct {
+	mu            sync.Mutex
+	notifications []Notification
+}
+
+func (notifier *fakeNotifier) Notify(ctx context.Context, notification Notification) error {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	notifier.notifications = append(notifier.notifications, notification)
+	return nil
+}
+
+func TestSetNotifiersFromEngine(t *testing.T) {
+	tests := map[string]struct {
+		data            map[string][]byte
+		annotations     map[string]string
+		expectedConfig  WebhookConfig
+		isNotifierAdded bool
+	}{
+		"Test Positive-1": {
+			data:            map[string][]byte{WebhookURLKey: []byte("http://fake-url"), WebhookSigningKey: []byte("fake-key"), WebhookRetriesKey: []byte("5"), WebhookTimeoutKey: []byte("3s")},
+			annotations:     map[string]string{NotifierSecretAnnotation: "fake-secret"},
+			expectedConfig:  WebhookConfig{URL: "http://fake-url", SigningKey: []byte("fake-key"), Retries: 5, Timeout: 3 * time.Second, Backoff: defaultWebhookBackoff},
+			isNotifierAdded: true,
+		},
+		"Test Positive-2": {
+			data:            map[string][]byte{WebhookURLKey: []byte("http://fake-url")},
+			annotations:     map[string]string{NotifierSecretAnnotation: "fake-secret"},
+			expectedConfig:  WebhookConfig{URL: "http://fake-url", Retries: DefaultWebhookRetries, Timeout: DefaultWebhookTimeout, Backoff: defaultWebhookBackoff},
+			isNotifierAdded: true,
+		},
+		"Test Positive-3": {
+			data:            map[string][]byte{WebhookURLKey: []byte("http://fake-url")},
+			annotations:     nil,
+			isNotifierAdded: false,
+		},
+		"Test Negative-1": {
+			data:            map[string][]byte{WebhookSigningKey: []byte("fake-key")},
+			annotations:     map[string]string{NotifierSecretAnnotation: "fake-secret"},
+			isNotifierAdded: false,
+		},
+		"Test Negative-2": {
+			data:            map[string][]byte{WebhookURLKey: []byte("http://fake-url"), WebhookTimeoutKey: []byte("soon")},
+			annotations:     map[string]string{NotifierSecretAnnotation: "fake-secret"},
+			isNotifierAdded: false,
+		},
+		"Test Negative-3": {
+			data:            map[string][]byte{WebhookURLKey: []byte("http://fake-url")},
+			annotations:     map[string]string{NotifierSecretAnnotation: "missing-secret"},
+			isNotifierAdded: false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:            "Fake Engine",
+				EngineNamespace: "Fake NameSpace",
+			}
+			client := CreateFakeClient(t)
+			secret := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fake-secret",
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Data: mock.data,
+			}
+			if _, err := client.KubeClient.CoreV1().Secrets(engineDetails.EngineNamespace).Create(context.Background(), secret, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("secret not created for %v test, err: %v", name, err)
+			}
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        engineDetails.Name,
+					Annotations: mock.annotations,
+				},
+			}
+			engineDetails.SetNotifiersFromEngine(chaosEngine, client)
+			if (len(engineDetails.Notifiers) == 1) != mock.isNotifierAdded {
+				t.Fatalf("Test %q failed: expected the notifier to be added: %v, got %v notifiers", name, mock.isNotifierAdded, len(engineDetails.Notifiers))
+			}
+			if !mock.isNotifierAdded {
+				return
+			}
+			webhook := engineDetails.Notifiers[0].(*WebhookNotifier)
+			if webhook.config.URL != mock.expectedConfig.URL || string(webhook.config.SigningKey) != string(mock.expectedConfig.SigningKey) ||
+				webhook.config.Retries != mock.expectedConfig.Retries || webhook.config.Timeout != mock.expectedConfig.Timeout || webhook.config.Backoff != mock.expectedConfig.Backoff {
+				t.Fatalf("Test %q failed: expected the config to be %+v, got %+v", name, mock.expectedConfig, webhook.config)
+			}
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	notifier := &fakeNotifier{}
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+		UID:             "Fake UID",
+		Notifiers:       []Notifier{notifier},
+	}
+	experiment := ExperimentDetails{
+		Name:     "Fake-Exp-Name",
+		JobName:  "fake-job-name",
+		Verdict:  "Fail",
+		FailStep: "fake-fail-step",
+		Attempt:  1,
+	}
+	client := CreateFakeClient(t)
+	engineDetails.StartNotifications(context.Background())
+
+	experiment.ExperimentCompleted(engineDetails, client)
+	experiment.ExperimentJobCleanUp("retain", engineDetails, client)
+	engineDetails.Notify(NotificationInitialized, nil, "fake-message")
+	engineDetails.DrainNotifications(NotificationsDrainTimeout)
+
+	expected := []NotificationType{NotificationCompleted, NotificationCleanedUp, NotificationInitialized}
+	if len(notifier.notifications) != len(expected) {
+		t.Fatalf("expected %v notifications, got %v", len(expected), len(notifier.notifications))
+	}
+	for i, notification := range notifier.notifications {
+		if notification.Type != expected[i] || notification.Engine != engineDetails.Name || notification.Time.IsZero() {
+			t.Fatalf("expected the notification %v to be %v, got %+v", i, expected[i], notification)
+		}
+	}
+	if completed := notifier.notifications[0]; completed.Experiment != experiment.Name || completed.Verdict != "Fail" || completed.FailStep != "fake-fail-step" {
+		t.Fatalf("expected the completed notification to carry the verdict, got %+v", completed)
+	}
+	if initialized := notifier.notifications[2]; initialized.Experiment != "" || initialized.Message != "fake-message" {
+		t.Fatalf("expected the initialized notification to belong to the chaosengine, got %+v", initialized)
+	}
+}
+
+func TestNotificationsWithUnreachableNotifier(t *testing.T) {
+	tests := map[string]struct {
+		interrupted bool
+	}{
+		"Test Positive-1": {
+			interrupted: false,
+		},
+		"Test Positive-2": {
+			interrupted: true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			notifier := &blockingNotifier{cancelled: make(chan struct{}, 1)}
+			engineDetails := EngineDetails{
+				Name:            "Fake Engine",
+				EngineNamespace: "Fake NameSpace",
+				Notifiers:       []Notifier{notifier},
+			}
+			ctx, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+			engineDetails.StartNotifications(ctx)
+
+			start := time.Now()
+			for i := 0; i < 2*notificationQueueSize; i++ {
+				engineDetails.Notify(NotificationInitialized, nil, "fake-message")
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("Test %q failed: expected the notifications to be queued right away, took %v", name, elapsed)
+			}
+			if mock.interrupted {
+				// the deliveries are cancelled at the exit deadline, which has already passed
+				cancel(&runnerInterruption{deadline: time.Now().Add(-InterruptExitTimeout)})
+				select {
+				case <-notifier.cancelled:
+				case <-time.After(5 * time.Second):
+					t.Fatalf("Test %q failed: expected the delivery to be cancelled at the exit deadline", name)
+				}
+			}
+
+			start = time.Now()
+			engineDetails.DrainNotifications(100 * time.Millisecond)
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("Test %q failed: expected the drain to be bounded by its timeout, took %v", name, elapsed)
+			}
+		})
+	}
+}
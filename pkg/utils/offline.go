@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// OfflineManifestsDirEnv is the ENV holding the directory of the manifests, which the chaos-runner
+// resolves the chaos resources from in offline mode, instead of the cluster
+const OfflineManifestsDirEnv = "OFFLINE_MANIFESTS_DIR"
+
+// readManifests decodes the supported manifests of the directory, split by the clientSet serving them
+func readManifests(dir string) ([]runtime.Object, []runtime.Object, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, errors.Errorf("unable to read the manifests directory: %v, error: %v", dir, err)
+	}
+	var kubeObjects, litmusObjects []runtime.Object
+	for _, file := range files {
+		extension := filepath.Ext(file.Name())
+		if file.IsDir() || (extension != ".yaml" && extension != ".yml" && extension != ".json") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, nil, errors.Errorf("unable to read the manifest: %v, error: %v", file.Name(), err)
+		}
+		decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+		for {
+			var document runtime.RawExtension
+			if err := decoder.Decode(&document); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return nil, nil, errors.Errorf("unable to decode the manifest: %v, error: %v", file.Name(), err)
+			}
+			if len(bytes.TrimSpace(document.Raw)) == 0 {
+				continue
+			}
+			object, isLitmusObject, err := decodeManifest(document.Raw)
+			if err != nil {
+				return nil, nil, errors.Errorf("unable to decode the manifest: %v, error: %v", file.Name(), err)
+			}
+			switch {
+			case object == nil:
+				continue
+			case isLitmusObject:
+				litmusObjects = append(litmusObjects, object)
+			default:
+				kubeObjects = append(kubeObjects, object)
+			}
+		}
+	}
+	return kubeObjects, litmusObjects, nil
+}
+
+// decodeManifest decodes a single manifest into the object of its kind
+// It returns a nil object for the kinds, which aren't needed by the chaos-runner
+func decodeManifest(raw []byte) (runtime.Object, bool, error) {
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, false, err
+	}
+	var object runtime.Object
+	isLitmusObject := false
+	switch typeMeta.Kind {
+	case "ChaosEngine":
+		object, isLitmusObject = &v1alpha1.ChaosEngine{}, true
+	case "ChaosExperiment":
+		object, isLitmusObject = &v1alpha1.ChaosExperiment{}, true
+	case "ConfigMap":
+		object = &corev1.ConfigMap{}
+	case "Secret":
+		object = &corev1.Secret{}
+	default:
+		log.Warnf("[skip]: %v manifest is not supported in offline mode", typeMeta.Kind)
+		return nil, false, nil
+	}
+	if err := yaml.Unmarshal(raw, object); err != nil {
+		return nil, false, err
+	}
+	return object, isLitmusObject, nil
+}
+
+// SetEngineDetailsFromManifests fills the engine details, which aren't provided through the ENVs in offline mode,
+// from the only ChaosEngine of the manifests
+// The configmap dry-run output is rejected, as there is no cluster to write the configmap into
+func (engineDetails *EngineDetails) SetEngineDetailsFromManifests(clients ClientSets) error {
+	if engineDetails.Render.Output == RenderOutputConfigMap {
+		return errors.Errorf("%v dry-run output not supported in offline mode, use %v instead", RenderOutputConfigMap, RenderOutputStdout)
+	}
+	if engineDetails.Name == "" {
+		engineList, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return errors.Errorf("unable to list the ChaosEngines, error: %v", err)
+		}
+		if len(engineList.Items) != 1 {
+			return errors.Errorf("unable to choose the ChaosEngine out of %v manifests, CHAOSENGINE should be provided", len(engineList.Items))
+		}
+		engineDetails.Name = engineList.Items[0].Name
+		engineDetails.EngineNamespace = engineList.Items[0].Namespace
+	}
+	chaosEngine, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Errorf("unable to get ChaosEngine name: %v, in namespace: %v, error: %v", engineDetails.Name, engineDetails.EngineNamespace, err)
+	}
+	if engineDetails.SvcAccount == "" {
+		engineDetails.SvcAccount = chaosEngine.Spec.ChaosServiceAccount
+	}
+	if len(engineDetails.Experiments) == 0 || strings.Join(engineDetails.Experiments, "") == "" {
+		engineDetails.Experiments = nil
+		for _, experiment := range chaosEngine.Spec.Experiments {
+			engineDetails.Experiments = append(engineDetails.Experiments, experiment.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build offline
+
+package utils
+
+import (
+	"k8s.io/client-go/kubernetes/fake"
+
+	litmusFakeClientset "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned/fake"
+)
+
+// GenerateClientSetFromManifests generates in-memory clientSets, preloaded with the ChaosEngine, ChaosExperiment,
+// ConfigMap and Secret manifests found inside the given directory, so that the chaos-runner resolves
+// everything from those files, without any cluster
+// The in-memory clientSets are the fake clientsets of client-go and litmus, as the render path reads the manifests
+// through the same clientSets as the cluster. They are only built into the chaos-runner with the offline build tag,
+// so that the default chaos-runner binary doesn't carry any test-only code
+func (clientSets *ClientSets) GenerateClientSetFromManifests(dir string) error {
+	kubeObjects, litmusObjects, err := readManifests(dir)
+	if err != nil {
+		return err
+	}
+	clientSets.KubeClient = fake.NewSimpleClientset(kubeObjects...)
+	clientSets.LitmusClient = litmusFakeClientset.NewSimpleClientset(litmusObjects...)
+	clientSets.EventRecorder = NewEventRecorder(clientSets.KubeClient)
+	return nil
+}
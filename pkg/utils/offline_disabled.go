@@ -0,0 +1,12 @@
+//go:build !offline
+
+package utils
+
+import (
+	"github.com/pkg/errors"
+)
+
+// GenerateClientSetFromManifests rejects the offline mode, which is only built into the chaos-runner with the offline build tag
+func (clientSets *ClientSets) GenerateClientSetFromManifests(dir string) error {
+	return errors.Errorf("unable to load the manifests directory: %v, offline mode is not built into the chaos-runner, build it with -tags offline", dir)
+}
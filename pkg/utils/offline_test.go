@@ -0,0 +1,131 @@
+//go:build offline
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerateClientSetFromManifests(t *testing.T) {
+	tests := map[string]struct {
+		dir   string
+		isErr bool
+	}{
+		"Test Positive-1": {
+			dir:   filepath.Join("testdata", "offline"),
+			isErr: false,
+		},
+		"Test Negative-1": {
+			dir:   filepath.Join("testdata", "missing"),
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			clients := ClientSets{}
+			err := clients.GenerateClientSetFromManifests(mock.dir)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			if mock.isErr {
+				return
+			}
+			if _, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines("default").Get(context.Background(), "nginx-chaos", metav1.GetOptions{}); err != nil {
+				t.Fatalf("Test %q failed: expected the chaosengine to be loaded, err: %v", name, err)
+			}
+			if _, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments("default").Get(context.Background(), "pod-delete", metav1.GetOptions{}); err != nil {
+				t.Fatalf("Test %q failed: expected the chaosexperiment to be loaded, err: %v", name, err)
+			}
+			if _, err := clients.KubeClient.CoreV1().ConfigMaps("default").Get(context.Background(), "pod-delete-config", metav1.GetOptions{}); err != nil {
+				t.Fatalf("Test %q failed: expected the configmap to be loaded, err: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestGenerateClientSetFromInvalidManifests(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "engine.yaml"), []byte("kind: ChaosEngine\nspec: [invalid"), 0600); err != nil {
+		t.Fatalf("fail to write the manifest, err: %v", err)
+	}
+	clients := ClientSets{}
+	if err := clients.GenerateClientSetFromManifests(dir); err == nil {
+		t.Fatalf("expected the invalid manifest to be reported")
+	}
+}
+
+func TestRenderExperimentsFromManifests(t *testing.T) {
+	clients := ClientSets{}
+	if err := clients.GenerateClientSetFromManifests(filepath.Join("testdata", "offline")); err != nil {
+		t.Fatalf("fail to load the manifests, err: %v", err)
+	}
+	engineDetails := EngineDetails{
+		Experiments: []string{""},
+		Render: RenderOptions{
+			Enabled: true,
+			Output:  RenderOutputStdout,
+			Format:  RenderFormatYAML,
+		},
+	}
+	if err := engineDetails.SetEngineDetailsFromManifests(clients); err != nil {
+		t.Fatalf("fail to set the engine details, err: %v", err)
+	}
+	if engineDetails.Name != "nginx-chaos" || engineDetails.EngineNamespace != "default" || engineDetails.SvcAccount != "pod-delete-sa" {
+		t.Fatalf("unexpected engine details: %+v", engineDetails)
+	}
+	experimentList, err := engineDetails.CreateExperimentList()
+	if err != nil {
+		t.Fatalf("fail to create the experiment list, err: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := engineDetails.RenderExperiments(context.Background(), experimentList, &stdout, clients); err != nil {
+		t.Fatalf("fail to render the experiments, err: %v", err)
+	}
+	var job batchv1.Job
+	if err := yaml.Unmarshal(stdout.Bytes(), &job); err != nil {
+		t.Fatalf("fail to decode the rendered job, err: %v", err)
+	}
+	envs := make(map[string]string)
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		envs[env.Name] = env.Value
+	}
+	if envs["TOTAL_CHAOS_DURATION"] != "30" || envs["FORCE"] != "true" {
+		t.Fatalf("expected the envs to be merged from the chaosexperiment and the chaosengine, got %v", envs)
+	}
+	isConfigMapMounted := false
+	for _, volume := range job.Spec.Template.Spec.Volumes {
+		if volume.ConfigMap != nil && volume.ConfigMap.Name == "pod-delete-config" {
+			isConfigMapMounted = true
+		}
+	}
+	if !isConfigMapMounted {
+		t.Fatalf("expected the configmap to be mounted, got %v", job.Spec.Template.Spec.Volumes)
+	}
+}
+
+func TestRenderExperimentsFromManifestsToConfigMap(t *testing.T) {
+	clients := ClientSets{}
+	if err := clients.GenerateClientSetFromManifests(filepath.Join("testdata", "offline")); err != nil {
+		t.Fatalf("fail to load the manifests, err: %v", err)
+	}
+	engineDetails := EngineDetails{
+		Experiments: []string{""},
+		Render: RenderOptions{
+			Enabled: true,
+			Output:  RenderOutputConfigMap,
+			Format:  RenderFormatYAML,
+		},
+	}
+	if err := engineDetails.SetEngineDetailsFromManifests(clients); err == nil {
+		t.Fatalf("expected the configmap dry-run output to be rejected in offline mode")
+	}
+}
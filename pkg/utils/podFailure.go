@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+const (
+	// PodFailureAnnotation is the per-experiment chaosengine annotation holding the classified failure of the chaos pod, e.g.
+	// pod-delete/pod-failure: 'ImagePullBackOff: container pod-delete-abcdef: Back-off pulling image "litmuschaos/go-runner:ci"'
+	PodFailureAnnotation = "pod-failure"
+
+	// ChaosPodFailure is the failure class of an experiment whose chaos pod failed for a classified reason
+	ChaosPodFailure = "ChaosPodFailure"
+)
+
+// terminalPodFailureReasons are the reasons which can't be recovered by waiting for the chaos pod
+var terminalPodFailureReasons = map[string]bool{
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CrashLoopBackOff":           true,
+	"OOMKilled":                  true,
+	"Evicted":                    true,
+}
+
+// transientPodFailureReasons are the reasons which may be recovered, e.g. once the image is pulled
+// or the cluster is scaled up, hence they are only reported once the statusCheckTimeout is reached
+var transientPodFailureReasons = map[string]bool{
+	"ErrImagePull":  true,
+	"Unschedulable": true,
+}
+
+// PodFailure is the classified cause of the failure of the chaos pod
+type PodFailure struct {
+	Reason  string
+	Message string
+	// Terminal is set if the chaos pod can't recover from the failure
+	Terminal bool
+}
+
+// Error implements the error interface
+func (failure *PodFailure) Error() string {
+	if failure.Message == "" {
+		return "chaos pod failed with reason: " + failure.Reason
+	}
+	return "chaos pod failed with reason: " + failure.Reason + ", message: " + failure.Message
+}
+
+// String returns the reason along with the message, as recorded in the chaosengine
+func (failure *PodFailure) String() string {
+	if failure.Message == "" {
+		return failure.Reason
+	}
+	return failure.Reason + ": " + failure.Message
+}
+
+// newPodFailure returns the pod failure for the given reason, or nil if the reason isn't classified
+func newPodFailure(reason, message string) *PodFailure {
+	switch {
+	case terminalPodFailureReasons[reason]:
+		return &PodFailure{Reason: reason, Message: message, Terminal: true}
+	case transientPodFailureReasons[reason]:
+		return &PodFailure{Reason: reason, Message: message}
+	}
+	return nil
+}
+
+// isPodFailureReason checks whether the reason is one of the classified failures of the chaos pod
+func isPodFailureReason(reason string) bool {
+	return terminalPodFailureReasons[reason] || transientPodFailureReasons[reason]
+}
+
+// classifyPodFailure looks for a known failure of the chaos pod, inside its status, its scheduling condition
+// and the waiting or terminated state of its containers. It returns nil if no failure is found
+func classifyPodFailure(pod *corev1.Pod) *PodFailure {
+	// the evicted pods are failed by the kubelet, with the reason set at the pod level
+	if failure := newPodFailure(pod.Status.Reason, pod.Status.Message); failure != nil {
+		return failure
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			if failure := newPodFailure(condition.Reason, condition.Message); failure != nil {
+				return failure
+			}
+		}
+	}
+
+	// the terminal failures take precedence over the transient ones, e.g. an OOMKilled sidecar over a pending image pull
+	var transientFailure *PodFailure
+	containers := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, container := range containers {
+		var failure *PodFailure
+		switch {
+		case container.State.Waiting != nil:
+			failure = newPodFailure(container.State.Waiting.Reason, containerMessage(container.Name, container.State.Waiting.Message))
+		case container.State.Terminated != nil:
+			failure = newPodFailure(container.State.Terminated.Reason, containerMessage(container.Name, container.State.Terminated.Message))
+		}
+		if failure == nil {
+			continue
+		}
+		if failure.Terminal {
+			return failure
+		}
+		if transientFailure == nil {
+			transientFailure = failure
+		}
+	}
+	return transientFailure
+}
+
+// containerMessage prefixes the message with the name of the container it refers to
+func containerMessage(containerName, message string) string {
+	return strings.TrimSuffix("container "+containerName+": "+message, ": ")
+}
+
+// FailExperiment cleans up the experiment job according to the jobCleanUpPolicy and marks the experiment
+// as failed with the classified cause of the failure of its chaos pod
+func (engineDetails EngineDetails) FailExperiment(experiment *ExperimentDetails, failure *PodFailure, clients ClientSets) {
+	log.Errorf("chaos pod of Chaos Experiment: %v failed, reason: %v", experiment.Name, failure.String())
+	experiment.Verdict = string(v1alpha1.ResultVerdictError)
+	experiment.ExperimentPodFailed(failure, engineDetails, clients)
+
+	annotations := map[string]string{
+		experimentAnnotation(experiment.Name, PodFailureAnnotation): failure.String(),
+	}
+	if err := engineDetails.PatchChaosEngineAnnotations(context.Background(), annotations, clients); err != nil {
+		log.Errorf("unable to record the pod failure of Chaos Experiment: %v, error: %v", experiment.Name, err)
+	}
+
+	engineDetails.haltExperiment(context.Background(), experiment, func(expStatus *ExperimentStatus, chaosPodName string) {
+		expStatus.PodFailedExperimentStatus(experiment.Name, engineDetails.Name, chaosPodName, failure.Reason)
+	}, jobCleanUpByPolicy, clients)
+}
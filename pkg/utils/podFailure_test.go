@@ -0,0 +1,294 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClassifyPodFailure(t *testing.T) {
+	tests := map[string]struct {
+		status         v1.PodStatus
+		expectedReason string
+		isTerminal     bool
+	}{
+		"Test Positive-1": {
+			status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "fake-job-name",
+						State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+					},
+				},
+			},
+			expectedReason: "ImagePullBackOff",
+			isTerminal:     true,
+		},
+		"Test Positive-2": {
+			status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "fake-job-name",
+						State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ErrImagePull"}},
+					},
+				},
+			},
+			expectedReason: "ErrImagePull",
+			isTerminal:     false,
+		},
+		"Test Positive-3": {
+			status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "fake-job-name",
+						State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+					},
+					{
+						Name:  "fake-sidecar",
+						State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+					},
+				},
+			},
+			expectedReason: "OOMKilled",
+			isTerminal:     true,
+		},
+		"Test Positive-4": {
+			status: v1.PodStatus{
+				Phase:   v1.PodFailed,
+				Reason:  "Evicted",
+				Message: "The node was low on resource: memory.",
+			},
+			expectedReason: "Evicted",
+			isTerminal:     true,
+		},
+		"Test Positive-5": {
+			status: v1.PodStatus{
+				Phase: v1.PodPending,
+				Conditions: []v1.PodCondition{
+					{
+						Type:    v1.PodScheduled,
+						Status:  v1.ConditionFalse,
+						Reason:  "Unschedulable",
+						Message: "0/3 nodes are available",
+					},
+				},
+			},
+			expectedReason: "Unschedulable",
+			isTerminal:     false,
+		},
+		"Test Positive-6": {
+			status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "fake-job-name",
+						State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CreateContainerConfigError"}},
+					},
+				},
+			},
+			expectedReason: "CreateContainerConfigError",
+			isTerminal:     true,
+		},
+		"Test Negative-1": {
+			status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "fake-job-name",
+						State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+					},
+				},
+			},
+		},
+		"Test Negative-2": {
+			status: v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "fake-job-name",
+						State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}},
+					},
+				},
+			},
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			failure := classifyPodFailure(&v1.Pod{Status: mock.status})
+			if mock.expectedReason == "" {
+				if failure != nil {
+					t.Fatalf("Test %q failed: expected no pod failure, got %v", name, failure)
+				}
+				return
+			}
+			if failure == nil {
+				t.Fatalf("Test %q failed: expected %v pod failure, got none", name, mock.expectedReason)
+			}
+			if failure.Reason != mock.expectedReason || failure.Terminal != mock.isTerminal {
+				t.Fatalf("Test %q failed: expected %v pod failure with terminal: %v, got %v with terminal: %v", name, mock.expectedReason, mock.isTerminal, failure.Reason, failure.Terminal)
+			}
+		})
+	}
+}
+
+func TestWatchChaosContainerForPodFailure(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+
+	tests := map[string]struct {
+		waitingReason  string
+		expectedReason string
+	}{
+		"Test Positive-1": {
+			waitingReason:  "ImagePullBackOff",
+			expectedReason: "ImagePullBackOff",
+		},
+		"Test Positive-2": {
+			waitingReason:  "ErrImagePull",
+			expectedReason: "ErrImagePull",
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			experiment := ExperimentDetails{
+				Name:               "Fake-Exp-Name",
+				Namespace:          "Fake NameSpace",
+				JobName:            "fake-job-name",
+				StatusCheckTimeout: 1,
+			}
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Status: v1alpha1.ChaosEngineStatus{
+					Experiments: []v1alpha1.ExperimentStatuses{
+						{
+							Name: experiment.Name,
+						},
+					},
+				},
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			chaosPod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "fake-chaos-pod",
+					Labels: map[string]string{"job-name": experiment.JobName},
+				},
+				Status: v1.PodStatus{
+					Phase: v1.PodPending,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name:  experiment.JobName,
+							State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: mock.waitingReason}},
+						},
+					},
+				},
+			}
+			if _, err := client.KubeClient.CoreV1().Pods(experiment.Namespace).Create(context.Background(), chaosPod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("fail to create chaos pod for %v test, err: %v", name, err)
+			}
+
+			err := engineDetails.WatchChaosContainerForCompletion(context.Background(), &experiment, client)
+			var podFailure *PodFailure
+			if !errors.As(err, &podFailure) {
+				t.Fatalf("Test %q failed: expected a pod failure, got %v", name, err)
+			}
+			if podFailure.Reason != mock.expectedReason {
+				t.Fatalf("Test %q failed: expected the pod failure reason to be %v, got %v", name, mock.expectedReason, podFailure.Reason)
+			}
+		})
+	}
+}
+
+func TestFailExperiment(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	experiment := ExperimentDetails{
+		Name:         "Fake-Exp-Name",
+		Namespace:    "Fake NameSpace",
+		JobName:      "fake-job-name",
+		ChaosPodName: "fake-job-name-abcde",
+	}
+	failure := &PodFailure{Reason: "ImagePullBackOff", Message: "container fake-job-name: Back-off pulling image", Terminal: true}
+
+	client := CreateFakeClient(t)
+	chaosEngine := &v1alpha1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.Name,
+			Namespace: engineDetails.EngineNamespace,
+		},
+		Spec: v1alpha1.ChaosEngineSpec{
+			JobCleanUpPolicy: v1alpha1.CleanUpPolicyDelete,
+		},
+		Status: v1alpha1.ChaosEngineStatus{
+			Experiments: []v1alpha1.ExperimentStatuses{
+				{
+					Name:   experiment.Name,
+					Status: v1alpha1.ExperimentStatusRunning,
+				},
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("engine not created, err: %v", err)
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      experiment.JobName,
+			Namespace: experiment.Namespace,
+		},
+	}
+	if _, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("fail to create exp job, err: %v", err)
+	}
+
+	engineDetails.FailExperiment(&experiment, failure, client)
+
+	chaosEngine, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("fail to get chaosengine after status patch, err: %v", err)
+	}
+	if chaosEngine.Status.Experiments[0].Status != v1alpha1.ExperimentStatus(failure.Reason) {
+		t.Fatalf("expected experiment status is %v, got %v", failure.Reason, chaosEngine.Status.Experiments[0].Status)
+	}
+	if annotation := chaosEngine.Annotations[experimentAnnotation(experiment.Name, PodFailureAnnotation)]; annotation != failure.String() {
+		t.Fatalf("expected the pod failure annotation to be %q, got %q", failure.String(), annotation)
+	}
+	if experiment.Verdict != string(v1alpha1.ResultVerdictError) {
+		t.Fatalf("expected the verdict to be %v, got %v", v1alpha1.ResultVerdictError, experiment.Verdict)
+	}
+	client.EventRecorder.Flush(EventsFlushTimeout)
+	events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("fail to list the events, err: %v", err)
+	}
+	isPodFailedEvent := false
+	for _, event := range events.Items {
+		if event.Reason == ExperimentPodFailedReason && event.Type == "Warning" {
+			isPodFailedEvent = true
+		}
+	}
+	if !isPodFailedEvent {
+		t.Fatalf("expected %v event to be generated", ExperimentPodFailedReason)
+	}
+	jobList, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(jobList.Items) != 0 {
+		t.Fatalf("expected the experiment job to be deleted, err: %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	batchlisters "k8s.io/client-go/listers/batch/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// chaosPodTracker keeps an informer cache of the experiment job and its pods,
+// and notifies on every change of them, instead of polling the api server
+// The informers need the list and watch verbs on the jobs and the pods, otherwise the tracker
+// falls back to polling them from the api server every watchFallbackPollInterval
+type chaosPodTracker struct {
+	jobName    string
+	namespace  string
+	clients    ClientSets
+	podLister  corelisters.PodLister
+	jobLister  batchlisters.JobLister
+	changes    chan struct{}
+	stopCh     chan struct{}
+	podFactory informers.SharedInformerFactory
+	jobFactory informers.SharedInformerFactory
+	// informersStopCh stops the informers, either along with the tracker or once they are forbidden
+	informersStopCh chan struct{}
+	// pollingCh is closed once the tracker falls back to polling
+	pollingCh   chan struct{}
+	pollingOnce sync.Once
+	stopOnce    sync.Once
+}
+
+// newChaosPodTracker starts the informers on the experiment job and on its pods, selected by the job-name label
+// It returns once the caches are synced, or the tracker fell back to polling,
+// or with the cause of the cancellation once the context is done
+func newChaosPodTracker(ctx context.Context, experiment *ExperimentDetails, clients ClientSets) (*chaosPodTracker, error) {
+	tracker := &chaosPodTracker{
+		jobName:         experiment.JobName,
+		namespace:       experiment.Namespace,
+		clients:         clients,
+		changes:         make(chan struct{}, 1),
+		stopCh:          make(chan struct{}),
+		informersStopCh: make(chan struct{}),
+		pollingCh:       make(chan struct{}),
+	}
+	tracker.podFactory = informers.NewSharedInformerFactoryWithOptions(clients.KubeClient, 0,
+		informers.WithNamespace(experiment.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = "job-name=" + experiment.JobName
+		}))
+	tracker.jobFactory = informers.NewSharedInformerFactoryWithOptions(clients.KubeClient, 0,
+		informers.WithNamespace(experiment.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", experiment.JobName).String()
+		}))
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { tracker.notify() },
+		UpdateFunc: func(interface{}, interface{}) { tracker.notify() },
+		DeleteFunc: func(interface{}) { tracker.notify() },
+	}
+	podInformer := tracker.podFactory.Core().V1().Pods()
+	podInformer.Informer().AddEventHandler(handler)
+	if err := podInformer.Informer().SetWatchErrorHandler(tracker.watchErrorHandler); err != nil {
+		return nil, err
+	}
+	tracker.podLister = podInformer.Lister()
+	jobInformer := tracker.jobFactory.Batch().V1().Jobs()
+	jobInformer.Informer().AddEventHandler(handler)
+	if err := jobInformer.Informer().SetWatchErrorHandler(tracker.watchErrorHandler); err != nil {
+		return nil, err
+	}
+	tracker.jobLister = jobInformer.Lister()
+
+	tracker.podFactory.Start(tracker.informersStopCh)
+	tracker.jobFactory.Start(tracker.informersStopCh)
+
+	// the caches are never synced, if the list is forbidden
+	syncStopCh := make(chan struct{})
+	synced := make(chan struct{})
+	defer close(synced)
+	go func() {
+		defer close(syncStopCh)
+		select {
+		case <-ctx.Done():
+		case <-tracker.pollingCh:
+		case <-synced:
+		}
+	}()
+	if !cache.WaitForCacheSync(syncStopCh, podInformer.Informer().HasSynced, jobInformer.Informer().HasSynced) && !tracker.isPolling() {
+		tracker.stop()
+		return nil, context.Cause(ctx)
+	}
+	return tracker, nil
+}
+
+// watchErrorHandler falls back to polling once the list or watch of the informers is forbidden
+// The other errors are retried by the informers with a backoff
+func (tracker *chaosPodTracker) watchErrorHandler(r *cache.Reflector, err error) {
+	if !k8serrors.IsForbidden(err) {
+		cache.DefaultWatchErrorHandler(r, err)
+		return
+	}
+	tracker.pollingOnce.Do(func() {
+		log.Warnf("unable to watch the Job: %v and its pods in namespace: %v, as the watch is forbidden, polling them every %v instead", tracker.jobName, tracker.namespace, watchFallbackPollInterval)
+		close(tracker.pollingCh)
+		tracker.stopInformers()
+		go tracker.poll()
+	})
+}
+
+// poll notifies a change every watchFallbackPollInterval, till the tracker is stopped
+func (tracker *chaosPodTracker) poll() {
+	ticker := time.NewTicker(watchFallbackPollInterval)
+	defer ticker.Stop()
+	for {
+		tracker.notify()
+		select {
+		case <-tracker.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isPolling checks whether the tracker fell back to polling
+func (tracker *chaosPodTracker) isPolling() bool {
+	select {
+	case <-tracker.pollingCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// notify records a change, without blocking the informer if a change is already pending
+func (tracker *chaosPodTracker) notify() {
+	select {
+	case tracker.changes <- struct{}{}:
+	default:
+	}
+}
+
+// stop stops the informers or the polling
+func (tracker *chaosPodTracker) stop() {
+	close(tracker.stopCh)
+	tracker.stopInformers()
+}
+
+// stopInformers stops the informers, either along with the tracker or once they are forbidden
+func (tracker *chaosPodTracker) stopInformers() {
+	tracker.stopOnce.Do(func() {
+		close(tracker.informersStopCh)
+	})
+}
+
+// pods returns the pods of the experiment job from the cache, or from the api server while polling,
+// sorted by their creation time
+// Once the given job is observed, the pods controlled by another job with the same name are left out
+func (tracker *chaosPodTracker) pods(ctx context.Context, job *batchv1.Job) ([]*corev1.Pod, error) {
+	var pods []*corev1.Pod
+	if tracker.isPolling() {
+		podList, err := tracker.clients.KubeClient.CoreV1().Pods(tracker.namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + tracker.jobName})
+		if err != nil {
+			return nil, err
+		}
+		for i := range podList.Items {
+			pods = append(pods, &podList.Items[i])
+		}
+	} else {
+		cached, err := tracker.podLister.Pods(tracker.namespace).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		pods = cached
+	}
+	if job != nil {
+		owned := pods[:0]
+		for _, pod := range pods {
+			if owner := metav1.GetControllerOf(pod); owner == nil || owner.UID == job.UID {
+				owned = append(owned, pod)
+			}
+		}
+		pods = owned
+	}
+	sortPodsByCreationTime(pods)
+	return pods, nil
+}
+
+// sortPodsByCreationTime sorts the pods from the oldest to the newest one
+func sortPodsByCreationTime(pods []*corev1.Pod) {
+	sort.SliceStable(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+}
+
+// job returns the experiment job from the cache, or from the api server while polling, or nil if it isn't observed
+func (tracker *chaosPodTracker) job(ctx context.Context) (*batchv1.Job, error) {
+	var job *batchv1.Job
+	var err error
+	if tracker.isPolling() {
+		job, err = tracker.clients.KubeClient.BatchV1().Jobs(tracker.namespace).Get(ctx, tracker.jobName, metav1.GetOptions{})
+	} else {
+		job, err = tracker.jobLister.Jobs(tracker.namespace).Get(tracker.jobName)
+	}
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+const (
+	// ReportingController is the name of the controller reporting the events of the chaos-runner
+	ReportingController = "litmuschaos.io/chaos-runner"
+	// eventsQPS and eventsBurst bound the rate of the events emitted by the chaos-runner
+	eventsQPS   = 5
+	eventsBurst = 25
+	// EventsFlushTimeout bounds the time spent on writing the queued events, once the chaos-runner is completed
+	EventsFlushTimeout = 5 * time.Second
+	// eventsIdlePeriod is the period without any event activity, after which the queued events are considered as written
+	eventsIdlePeriod = 200 * time.Millisecond
+)
+
+// EventRecorder emits the events of the chaos-runner through the event broadcaster of client-go,
+// which aggregates the repeated events into series. The events.k8s.io/v1 API is used if served by the cluster,
+// otherwise the events fall back to the core/v1 API. They also fall back to the core/v1 API once an events.k8s.io/v1 write
+// is forbidden, as the stock service accounts only grant the events inside the core group
+type EventRecorder struct {
+	kubeClient kubernetes.Interface
+	limiter    flowcontrol.RateLimiter
+	activity   *eventActivity
+	// mu guards the recorder and the shutdown, which are replaced once the events fall back to the core/v1 API
+	mu       sync.Mutex
+	recorder events.EventRecorder
+	// shutdown stops the event broadcasters, no event is emitted again once they are stopped
+	shutdown func()
+	stopped  bool
+	fallback sync.Once
+	once     sync.Once
+}
+
+// NewEventRecorder creates the event recorder and starts writing the events to the cluster
+func NewEventRecorder(kubeClient kubernetes.Interface) *EventRecorder {
+	eventRecorder := &EventRecorder{
+		kubeClient: kubeClient,
+		limiter:    flowcontrol.NewTokenBucketRateLimiter(eventsQPS, eventsBurst),
+		activity:   &eventActivity{},
+	}
+	if _, err := kubeClient.Discovery().ServerResourcesForGroupVersion(eventsv1.SchemeGroupVersion.String()); err == nil {
+		sink := &eventsV1Sink{sink: &events.EventSinkImpl{Interface: kubeClient.EventsV1()}, activity: eventRecorder.activity, onForbidden: eventRecorder.fallbackToCoreV1}
+		broadcaster := events.NewBroadcaster(sink)
+		stopCh := make(chan struct{})
+		broadcaster.StartRecordingToSink(stopCh)
+		eventRecorder.recorder = broadcaster.NewRecorder(scheme.Scheme, ReportingController)
+		// stopping the recording also stops the underlying watch of the broadcaster, which races with its Shutdown
+		eventRecorder.shutdown = func() { close(stopCh) }
+		return eventRecorder
+	}
+	eventRecorder.recorder, eventRecorder.shutdown = newCoreV1Recorder(kubeClient, eventRecorder.activity)
+	return eventRecorder
+}
+
+// newCoreV1Recorder starts the core/v1 event broadcaster, and returns its recorder along with its shutdown
+// The source of the events is the chaos-runner of the chaosengine, as reported before the events.k8s.io/v1 API was supported
+func newCoreV1Recorder(kubeClient kubernetes.Interface, activity *eventActivity) (events.EventRecorder, func()) {
+	hostname, _ := os.Hostname()
+	component := ReportingController
+	if engineName := os.Getenv("CHAOSENGINE"); engineName != "" {
+		component = engineName + "-runner"
+	}
+	// the spam filter of the core/v1 broadcaster is aligned with the rate limit of the recorder
+	broadcaster := record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{QPS: eventsQPS, BurstSize: eventsBurst})
+	broadcaster.StartRecordingToSink(&coreV1Sink{kubeClient: kubeClient, activity: activity})
+	recorder := record.NewEventRecorderAdapter(broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: component, Host: hostname}))
+	return recorder, broadcaster.Shutdown
+}
+
+// fallbackToCoreV1 switches the recorder to the core/v1 event broadcaster, once the write of the given event is forbidden,
+// and emits the event again through it. The events.k8s.io/v1 broadcaster is stopped along with the recorder,
+// so that its queued events are emitted again as well
+func (eventRecorder *EventRecorder) fallbackToCoreV1(event *eventsv1.Event) {
+	eventRecorder.fallback.Do(func() {
+		log.Warnf("unable to write the events through the %v API, as it is forbidden, writing them through the core/v1 API instead", eventsv1.SchemeGroupVersion.String())
+		recorder, shutdown := newCoreV1Recorder(eventRecorder.kubeClient, eventRecorder.activity)
+		eventRecorder.mu.Lock()
+		defer eventRecorder.mu.Unlock()
+		stopEventsV1 := eventRecorder.shutdown
+		eventRecorder.recorder = recorder
+		eventRecorder.shutdown = func() {
+			stopEventsV1()
+			shutdown()
+		}
+	})
+	eventRecorder.mu.Lock()
+	defer eventRecorder.mu.Unlock()
+	if eventRecorder.stopped {
+		return
+	}
+	eventRecorder.activity.touch()
+	var related runtime.Object
+	if event.Related != nil {
+		related = event.Related
+	}
+	eventRecorder.recorder.Eventf(&event.Regarding, related, event.Type, event.Reason, event.Action, "%s", event.Note)
+}
+
+// current returns the recorder the events are currently emitted through
+func (eventRecorder *EventRecorder) current() events.EventRecorder {
+	eventRecorder.mu.Lock()
+	defer eventRecorder.mu.Unlock()
+	return eventRecorder.recorder
+}
+
+// Eventf emits an event regarding the given object, the related object is optional
+// The events exceeding the rate limit are dropped
+func (eventRecorder *EventRecorder) Eventf(regarding, related *corev1.ObjectReference, eventType, reason, action, note string) {
+	if !eventRecorder.limiter.TryAccept() {
+		log.Warnf("[skip]: dropping %v event of %v: %v, as the event rate limit is exceeded", reason, regarding.Kind, regarding.Name)
+		return
+	}
+	eventRecorder.activity.touch()
+	var relatedObject runtime.Object
+	if related != nil {
+		relatedObject = related
+	}
+	eventRecorder.current().Eventf(regarding, relatedObject, eventType, reason, action, "%s", note)
+}
+
+// Flush waits till the emitted events are written, bounded by the given timeout
+func (eventRecorder *EventRecorder) Flush(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) && !eventRecorder.activity.isIdle() {
+		time.Sleep(eventsIdlePeriod / 4)
+	}
+}
+
+// Shutdown writes the emitted events, bounded by the given timeout, and stops the event broadcaster
+func (eventRecorder *EventRecorder) Shutdown(timeout time.Duration) {
+	eventRecorder.once.Do(func() {
+		eventRecorder.Flush(timeout)
+		eventRecorder.mu.Lock()
+		shutdown := eventRecorder.shutdown
+		eventRecorder.stopped = true
+		eventRecorder.mu.Unlock()
+		shutdown()
+	})
+}
+
+// objectReference returns the reference of the chaosengine, which all the events of the chaos-runner are attached to
+func (engineDetails EngineDetails) objectReference() *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: "litmuschaos.io/v1alpha1",
+		Kind:       "ChaosEngine",
+		Name:       engineDetails.Name,
+		Namespace:  engineDetails.EngineNamespace,
+		UID:        clientTypes.UID(engineDetails.UID),
+	}
+}
+
+// eventObjects returns the references of the experiment job and chaos pod of the current attempt, if known yet
+func (expDetails ExperimentDetails) eventObjects() []*corev1.ObjectReference {
+	var objects []*corev1.ObjectReference
+	if expDetails.jobReference != nil {
+		objects = append(objects, expDetails.jobReference)
+	}
+	if expDetails.podReference != nil {
+		objects = append(objects, expDetails.podReference)
+	}
+	return objects
+}
+
+// jobReference returns the reference of the experiment job
+func jobReference(job *batchv1.Job) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion:      "batch/v1",
+		Kind:            "Job",
+		Name:            job.Name,
+		Namespace:       job.Namespace,
+		UID:             job.UID,
+		ResourceVersion: job.ResourceVersion,
+	}
+}
+
+// podReference returns the reference of the chaos pod
+func podReference(pod *corev1.Pod) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion:      "v1",
+		Kind:            "Pod",
+		Name:            pod.Name,
+		Namespace:       pod.Namespace,
+		UID:             pod.UID,
+		ResourceVersion: pod.ResourceVersion,
+	}
+}
+
+// eventActivity tracks the events being written by the sink, so that they can be flushed
+type eventActivity struct {
+	mu           sync.Mutex
+	inFlight     int
+	lastActivity time.Time
+}
+
+func (activity *eventActivity) touch() {
+	activity.mu.Lock()
+	defer activity.mu.Unlock()
+	activity.lastActivity = time.Now()
+}
+
+// track runs the given write of the sink, while recording it as in flight
+func (activity *eventActivity) track(write func()) {
+	activity.mu.Lock()
+	activity.inFlight++
+	activity.mu.Unlock()
+	defer func() {
+		activity.mu.Lock()
+		defer activity.mu.Unlock()
+		activity.inFlight--
+		activity.lastActivity = time.Now()
+	}()
+	write()
+}
+
+func (activity *eventActivity) isIdle() bool {
+	activity.mu.Lock()
+	defer activity.mu.Unlock()
+	return activity.inFlight == 0 && time.Since(activity.lastActivity) >= eventsIdlePeriod
+}
+
+// eventsV1Sink is the events.k8s.io/v1 sink, tracking the written events
+// The events whose creation is forbidden are handed over to onForbidden
+type eventsV1Sink struct {
+	sink        events.EventSink
+	activity    *eventActivity
+	onForbidden func(event *eventsv1.Event)
+}
+
+func (s *eventsV1Sink) Create(event *eventsv1.Event) (created *eventsv1.Event, err error) {
+	s.activity.track(func() {
+		if created, err = s.sink.Create(event); k8serrors.IsForbidden(err) {
+			s.onForbidden(event)
+		}
+	})
+	return created, err
+}
+
+func (s *eventsV1Sink) Update(event *eventsv1.Event) (updated *eventsv1.Event, err error) {
+	s.activity.track(func() { updated, err = s.sink.Update(event) })
+	return updated, err
+}
+
+func (s *eventsV1Sink) Patch(event *eventsv1.Event, data []byte) (patched *eventsv1.Event, err error) {
+	s.activity.track(func() { patched, err = s.sink.Patch(event, data) })
+	return patched, err
+}
+
+// coreV1Sink is the core/v1 sink, tracking the written events
+// The events are written inside the namespace of the event, as the chaos-runner is namespace scoped
+type coreV1Sink struct {
+	kubeClient kubernetes.Interface
+	activity   *eventActivity
+}
+
+func (s *coreV1Sink) Create(event *corev1.Event) (created *corev1.Event, err error) {
+	s.activity.track(func() { created, err = s.kubeClient.CoreV1().Events(event.Namespace).CreateWithEventNamespace(event) })
+	return created, err
+}
+
+func (s *coreV1Sink) Update(event *corev1.Event) (updated *corev1.Event, err error) {
+	s.activity.track(func() { updated, err = s.kubeClient.CoreV1().Events(event.Namespace).UpdateWithEventNamespace(event) })
+	return updated, err
+}
+
+func (s *coreV1Sink) Patch(event *corev1.Event, data []byte) (patched *corev1.Event, err error) {
+	s.activity.track(func() {
+		patched, err = s.kubeClient.CoreV1().Events(event.Namespace).PatchWithEventNamespace(event, data)
+	})
+	return patched, err
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNewEventRecorder(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+		UID:             "Fake UID",
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fake-pod-name",
+			Namespace: engineDetails.EngineNamespace,
+			UID:       "fake-pod-uid",
+		},
+	}
+
+	tests := map[string]struct {
+		eventsV1            bool
+		isEventsV1Forbidden bool
+		expectedEvents      int
+	}{
+		"Test Positive-1": {
+			eventsV1:       true,
+			expectedEvents: 2,
+		},
+		"Test Positive-2": {
+			eventsV1:       false,
+			expectedEvents: 2,
+		},
+		"Test Positive-3": {
+			eventsV1:            true,
+			isEventsV1Forbidden: true,
+			expectedEvents:      2,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CHAOSENGINE", engineDetails.Name)
+			kubeClient := fake.NewSimpleClientset([]runtime.Object{}...)
+			if mock.eventsV1 {
+				kubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+					{GroupVersion: eventsv1.SchemeGroupVersion.String()},
+				}
+			}
+			if mock.isEventsV1Forbidden {
+				// the service account only grants the events inside the core group
+				kubeClient.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					if action.GetResource().Group != eventsv1.GroupName {
+						return false, nil, nil
+					}
+					return true, nil, k8serrors.NewForbidden(action.GetResource().GroupResource(), "", errors.New("events.k8s.io events are not granted"))
+				})
+			}
+			client := ClientSets{KubeClient: kubeClient, EventRecorder: NewEventRecorder(kubeClient)}
+			defer client.EventRecorder.Shutdown(EventsFlushTimeout)
+
+			event := EventAttributes{
+				Reason:  "fake-reason",
+				Message: "fake-message",
+				Type:    "Normal",
+				Related: []*corev1.ObjectReference{podReference(pod)},
+			}
+			if err := engineDetails.GenerateEvents(&event, client); err != nil {
+				t.Fatalf("%v fail to generate events, err: %v", name, err)
+			}
+			client.EventRecorder.Flush(EventsFlushTimeout)
+
+			var regarding []string
+			if mock.eventsV1 && !mock.isEventsV1Forbidden {
+				events, err := kubeClient.EventsV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+				if err != nil {
+					t.Fatalf("%v fail to get events, err: %v", name, err)
+				}
+				for _, e := range events.Items {
+					if e.ReportingController != ReportingController || e.Action != event.Reason {
+						t.Fatalf("Test %q failed: expected the event to be reported by %v, got %v", name, ReportingController, e.ReportingController)
+					}
+					regarding = append(regarding, e.Regarding.Kind)
+				}
+			} else {
+				events, err := kubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+				if err != nil {
+					t.Fatalf("%v fail to get events, err: %v", name, err)
+				}
+				for _, e := range events.Items {
+					if e.Source.Component != engineDetails.Name+"-runner" {
+						t.Fatalf("Test %q failed: expected the event to be sourced from %v-runner, got %v", name, engineDetails.Name, e.Source.Component)
+					}
+					regarding = append(regarding, e.InvolvedObject.Kind)
+				}
+			}
+			if len(regarding) != mock.expectedEvents {
+				t.Fatalf("Test %q failed: expected %v events, got %v regarding %v", name, mock.expectedEvents, len(regarding), regarding)
+			}
+		})
+	}
+}
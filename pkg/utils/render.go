@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RenderOutputStdout writes the rendered jobs to the stdout of the chaos-runner
+	RenderOutputStdout = "stdout"
+	// RenderOutputConfigMap writes the rendered jobs into a configmap in the chaos namespace
+	RenderOutputConfigMap = "configmap"
+	// RenderFormatYAML renders the jobs as a multi-document YAML
+	RenderFormatYAML = "yaml"
+	// RenderFormatJSON renders a single job as a JSON object, and several jobs as a JSON List
+	RenderFormatJSON = "json"
+)
+
+// RenderOptions defines the dry-run mode, which renders the experiment jobs instead of launching them
+type RenderOptions struct {
+	// Enabled turns the chaos-runner into the dry-run mode
+	Enabled bool
+	// Output is the destination of the rendered jobs, either stdout or configmap
+	Output string
+	// Format is the format of the rendered jobs, either yaml or json
+	Format string
+}
+
+// SetRenderOptions sets the dry-run mode from the ENVs
+func (engineDetails *EngineDetails) SetRenderOptions() *EngineDetails {
+	engineDetails.Render.Enabled, _ = strconv.ParseBool(os.Getenv("DRY_RUN"))
+	engineDetails.Render.Output = getEnv("DRY_RUN_OUTPUT", RenderOutputStdout)
+	engineDetails.Render.Format = getEnv("DRY_RUN_FORMAT", RenderFormatYAML)
+	return engineDetails
+}
+
+// RenderedJobsConfigMapName returns the name of the configmap holding the rendered jobs
+func (engineDetails EngineDetails) RenderedJobsConfigMapName() string {
+	return engineDetails.Name + "-rendered-jobs"
+}
+
+// RenderExperiments resolves every experiment the same way as a real run, and renders their jobs
+// instead of launching them. Neither the chaosengine status is patched, nor any event is generated
+func (engineDetails EngineDetails) RenderExperiments(ctx context.Context, experimentList []ExperimentDetails, stdout io.Writer, clients ClientSets) error {
+	var jobs []*batchv1.Job
+	for i := range experimentList {
+		job, err := engineDetails.renderExperimentJob(ctx, &experimentList[i], clients)
+		if err != nil {
+			return errors.Errorf("unable to render the job of Chaos Experiment: %v, error: %v", experimentList[i].Name, err)
+		}
+		jobs = append(jobs, job)
+	}
+	return engineDetails.RenderJobs(jobs, stdout, clients)
+}
+
+// renderExperimentJob runs the resolution pipeline of the experiment and builds its job
+func (engineDetails EngineDetails) renderExperimentJob(ctx context.Context, experiment *ExperimentDetails, clients ClientSets) (*batchv1.Job, error) {
+	if err := experiment.SetValueFromChaosResources(&engineDetails, clients); err != nil {
+		return nil, err
+	}
+	if err := experiment.SetENV(ctx, engineDetails, clients); err != nil {
+		return nil, err
+	}
+	if err := experiment.SetSideCarDetails(engineDetails.Name, clients); err != nil {
+		return nil, err
+	}
+	if err := experiment.PatchResources(engineDetails, clients); err != nil {
+		return nil, err
+	}
+	experiment.SetAttempt(experiment.Attempt)
+	return BuildExperimentJob(experiment)
+}
+
+// RenderJobs writes the experiment jobs to the destination of the dry-run mode
+func (engineDetails EngineDetails) RenderJobs(jobs []*batchv1.Job, stdout io.Writer, clients ClientSets) error {
+	for _, job := range jobs {
+		job.TypeMeta = metav1.TypeMeta{APIVersion: "batch/v1", Kind: "Job"}
+	}
+	switch engineDetails.Render.Output {
+	case RenderOutputStdout:
+		rendered, err := renderJobs(jobs, engineDetails.Render.Format)
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(rendered)
+		return err
+	case RenderOutputConfigMap:
+		data := make(map[string]string, len(jobs))
+		for _, job := range jobs {
+			rendered, err := renderJobs([]*batchv1.Job{job}, engineDetails.Render.Format)
+			if err != nil {
+				return err
+			}
+			data[job.Name+"."+engineDetails.Render.Format] = string(rendered)
+		}
+		return engineDetails.applyRenderedJobsConfigMap(data, clients)
+	default:
+		return errors.Errorf("%v dry-run output not supported", engineDetails.Render.Output)
+	}
+}
+
+// renderJobs encodes the jobs in the given format
+func renderJobs(jobs []*batchv1.Job, format string) ([]byte, error) {
+	switch format {
+	case RenderFormatYAML:
+		var rendered []byte
+		for i, job := range jobs {
+			document, err := yaml.Marshal(job)
+			if err != nil {
+				return nil, errors.Errorf("unable to render Job: %v, error: %v", job.Name, err)
+			}
+			if i != 0 {
+				rendered = append(rendered, []byte("---\n")...)
+			}
+			rendered = append(rendered, document...)
+		}
+		return rendered, nil
+	case RenderFormatJSON:
+		if len(jobs) == 1 {
+			return json.MarshalIndent(jobs[0], "", "  ")
+		}
+		list := struct {
+			metav1.TypeMeta `json:",inline"`
+			Items           []*batchv1.Job `json:"items"`
+		}{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+			Items:    jobs,
+		}
+		return json.MarshalIndent(list, "", "  ")
+	default:
+		return nil, errors.Errorf("%v dry-run format not supported", format)
+	}
+}
+
+// applyRenderedJobsConfigMap creates or replaces the configmap holding the rendered jobs
+func (engineDetails EngineDetails) applyRenderedJobsConfigMap(data map[string]string, clients ClientSets) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.RenderedJobsConfigMapName(),
+			Namespace: engineDetails.EngineNamespace,
+			Labels:    map[string]string{"chaosUID": engineDetails.UID},
+		},
+		Data: data,
+	}
+	configMaps := clients.KubeClient.CoreV1().ConfigMaps(engineDetails.EngineNamespace)
+	_, err := configMaps.Create(context.Background(), configMap, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		_, err = configMaps.Update(context.Background(), configMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return errors.Errorf("unable to write the rendered jobs into ConfigMap: %v, error: %v", configMap.Name, err)
+	}
+	return nil
+}
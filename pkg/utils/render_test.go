@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRenderExperiments(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "fake-engine",
+		EngineNamespace: "fake-namespace",
+		UID:             "fake-uid",
+		SvcAccount:      "litmus-admin",
+		Experiments:     []string{"pod-delete"},
+		Render: RenderOptions{
+			Enabled: true,
+			Output:  RenderOutputStdout,
+			Format:  RenderFormatYAML,
+		},
+	}
+	client := CreateFakeClient(t)
+	chaosEngine := &v1alpha1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.Name,
+			Namespace: engineDetails.EngineNamespace,
+		},
+		Spec: v1alpha1.ChaosEngineSpec{
+			Experiments: []v1alpha1.ExperimentList{
+				{
+					Name: "pod-delete",
+					Spec: v1alpha1.ExperimentAttributes{
+						Components: v1alpha1.ExperimentComponents{
+							ENV: []v1.EnvVar{{Name: "TOTAL_CHAOS_DURATION", Value: "30"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("engine not created, err: %v", err)
+	}
+	chaosExperiment := &v1alpha1.ChaosExperiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-delete",
+			Namespace: engineDetails.EngineNamespace,
+		},
+		Spec: v1alpha1.ChaosExperimentSpec{
+			Definition: v1alpha1.ExperimentDef{
+				Image:   "litmuschaos/go-runner:latest",
+				Command: []string{"/bin/bash"},
+				Args:    []string{"-c", "./experiments -name pod-delete"},
+				Labels:  map[string]string{"name": "pod-delete"},
+				ENVList: []v1.EnvVar{{Name: "TOTAL_CHAOS_DURATION", Value: "15"}},
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(engineDetails.EngineNamespace).Create(context.Background(), chaosExperiment, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("experiment not created, err: %v", err)
+	}
+	experimentList, err := engineDetails.CreateExperimentList()
+	if err != nil {
+		t.Fatalf("fail to create the experiment list, err: %v", err)
+	}
+
+	var stdout bytes.Buffer
+	if err := engineDetails.RenderExperiments(context.Background(), experimentList, &stdout, client); err != nil {
+		t.Fatalf("fail to render the experiments, err: %v", err)
+	}
+
+	var job batchv1.Job
+	if err := yaml.Unmarshal(stdout.Bytes(), &job); err != nil {
+		t.Fatalf("fail to decode the rendered job, err: %v", err)
+	}
+	if job.Kind != "Job" || job.Name != experimentList[0].JobName || job.Labels["chaosUID"] != engineDetails.UID {
+		t.Fatalf("unexpected rendered job: %v", stdout.String())
+	}
+	if job.Spec.ActiveDeadlineSeconds == nil || *job.Spec.ActiveDeadlineSeconds != int64(30) {
+		t.Fatalf("expected the activeDeadlineSeconds to be derived from the overridden TOTAL_CHAOS_DURATION, got %v", job.Spec.ActiveDeadlineSeconds)
+	}
+	isOverridden := false
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		if env.Name == "TOTAL_CHAOS_DURATION" && env.Value == "30" {
+			isOverridden = true
+		}
+	}
+	if !isOverridden {
+		t.Fatalf("expected the env to be overridden from the chaosengine, got %v", job.Spec.Template.Spec.Containers[0].Env)
+	}
+
+	jobList, err := client.KubeClient.BatchV1().Jobs(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(jobList.Items) != 0 {
+		t.Fatalf("expected no job to be launched, err: %v", err)
+	}
+	client.EventRecorder.Flush(EventsFlushTimeout)
+	events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil || len(events.Items) != 0 {
+		t.Fatalf("expected no event to be generated, err: %v", err)
+	}
+}
+
+func TestRenderJobs(t *testing.T) {
+	jobs := []*batchv1.Job{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-delete-abcdef"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-cpu-hog-abcdef"}},
+	}
+
+	tests := map[string]struct {
+		output string
+		format string
+		isErr  bool
+	}{
+		"Test Positive-1": {
+			output: RenderOutputStdout,
+			format: RenderFormatYAML,
+			isErr:  false,
+		},
+		"Test Positive-2": {
+			output: RenderOutputStdout,
+			format: RenderFormatJSON,
+			isErr:  false,
+		},
+		"Test Positive-3": {
+			output: RenderOutputConfigMap,
+			format: RenderFormatYAML,
+			isErr:  false,
+		},
+		"Test Negative-1": {
+			output: RenderOutputStdout,
+			format: "xml",
+			isErr:  true,
+		},
+		"Test Negative-2": {
+			output: "file",
+			format: RenderFormatYAML,
+			isErr:  true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			engineDetails := EngineDetails{
+				Name:            "fake-engine",
+				EngineNamespace: "fake-namespace",
+				Render: RenderOptions{
+					Enabled: true,
+					Output:  mock.output,
+					Format:  mock.format,
+				},
+			}
+			var stdout bytes.Buffer
+			err := engineDetails.RenderJobs(jobs, &stdout, client)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			if mock.isErr {
+				return
+			}
+
+			switch {
+			case mock.output == RenderOutputConfigMap:
+				configMap, err := client.KubeClient.CoreV1().ConfigMaps(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.RenderedJobsConfigMapName(), metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("Test %q failed: fail to get the rendered jobs configmap, err: %v", name, err)
+				}
+				if len(configMap.Data) != len(jobs) || !strings.Contains(configMap.Data["pod-delete-abcdef.yaml"], "kind: Job") {
+					t.Fatalf("Test %q failed: unexpected rendered jobs configmap data: %v", name, configMap.Data)
+				}
+			case mock.format == RenderFormatJSON:
+				var list struct {
+					Kind  string        `json:"kind"`
+					Items []batchv1.Job `json:"items"`
+				}
+				if err := json.Unmarshal(stdout.Bytes(), &list); err != nil || list.Kind != "List" || len(list.Items) != len(jobs) {
+					t.Fatalf("Test %q failed: unexpected rendered jobs: %v, err: %v", name, stdout.String(), err)
+				}
+			default:
+				if documents := strings.Split(stdout.String(), "---\n"); len(documents) != len(jobs) {
+					t.Fatalf("Test %q failed: expected %v documents, got %v", name, len(jobs), len(documents))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// ExperimentLabel is the label holding the name of the experiment, which launched the job
+const ExperimentLabel = "chaosExperiment"
+
+// ResumeExperiments reconciles the experiments with the chaosengine status and the experiment jobs,
+// left behind by a previous chaos-runner pod of the same run, e.g. after the runner pod restarted.
+// The experiments which already finished are marked as finished, and the job of the experiments
+// which were still in flight is adopted, to be watched instead of being launched again
+// The finished jobs retained by the jobCleanUpPolicy from the earlier runs of the chaosengine are not adopted
+func (engineDetails EngineDetails) ResumeExperiments(experimentList []ExperimentDetails, clients ClientSets) error {
+	expEngine, err := engineDetails.GetChaosEngine(context.Background(), clients)
+	if err != nil {
+		return errors.Errorf("unable to get ChaosEngine, error: %v", err)
+	}
+	for i := range experimentList {
+		experiment := &experimentList[i]
+		index := checkStatusListForExp(expEngine.Status.Experiments, experiment.Name)
+		if index != -1 && isFinishedExperimentStatus(expEngine.Status.Experiments[index].Status) {
+			log.Infof("[resume]: Chaos Experiment: %v is already finished with verdict: %v", experiment.Name, expEngine.Status.Experiments[index].Verdict)
+			experiment.Finished = true
+			experiment.Verdict = expEngine.Status.Experiments[index].Verdict
+		}
+	}
+
+	jobList, err := clients.KubeClient.BatchV1().Jobs(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{LabelSelector: "chaosUID=" + engineDetails.UID})
+	if err != nil {
+		return errors.Errorf("unable to list the experiment jobs in namespace: %v, error: %v", engineDetails.EngineNamespace, err)
+	}
+	// the latest job of an experiment belongs to its latest attempt
+	jobs := jobList.Items
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[j].CreationTimestamp.Before(&jobs[i].CreationTimestamp)
+	})
+	for _, job := range jobs {
+		experiment := findExperimentForJob(experimentList, job)
+		if experiment == nil || experiment.Finished || experiment.Adopted || !isInFlightJob(job, expEngine.Status.Experiments, experiment.Name) {
+			continue
+		}
+		experiment.AdoptJob(job)
+		log.Infof("[resume]: adopting Job: %v of Chaos Experiment: %v, attempt: %v", experiment.JobName, experiment.Name, experiment.Attempt)
+	}
+	return nil
+}
+
+// isInFlightJob checks whether the job belongs to the in-flight attempt of the experiment, i.e. the job is still active,
+// or its chaos pod is referenced by the Running status entry of the experiment, while its result is yet to be recorded
+func isInFlightJob(job batchv1.Job, statuses []v1alpha1.ExperimentStatuses, experimentName string) bool {
+	if getJobCondition(&job, batchv1.JobComplete) == nil && getJobCondition(&job, batchv1.JobFailed) == nil {
+		return true
+	}
+	index := checkStatusListForExp(statuses, experimentName)
+	return index != -1 && statuses[index].Status == v1alpha1.ExperimentStatusRunning && strings.HasPrefix(statuses[index].ExpPod, job.Name+"-")
+}
+
+// AdoptJob makes the experiment watch the given job, launched by a previous chaos-runner pod
+func (expDetails *ExperimentDetails) AdoptJob(job batchv1.Job) *ExperimentDetails {
+	expDetails.Adopted = true
+	expDetails.JobName = job.Name
+	if attempt, err := strconv.Atoi(job.Labels[AttemptLabel]); err == nil && attempt > 0 {
+		expDetails.Attempt = attempt
+	}
+	return expDetails
+}
+
+// findExperimentForJob returns the experiment, which launched the given job
+// The jobs launched before the experiment label was introduced are matched by their name
+func findExperimentForJob(experimentList []ExperimentDetails, job batchv1.Job) *ExperimentDetails {
+	for i := range experimentList {
+		experiment := &experimentList[i]
+		if name, ok := job.Labels[ExperimentLabel]; ok {
+			if name == experiment.Name {
+				return experiment
+			}
+			continue
+		}
+		// the job name is the experiment name followed by a random suffix of length 6
+		if strings.HasPrefix(job.Name, experiment.Name+"-") && len(job.Name) == len(experiment.Name)+7 {
+			return experiment
+		}
+	}
+	return nil
+}
+
+// isFinishedExperimentStatus checks whether the experiment doesn't need to be executed again
+func isFinishedExperimentStatus(status v1alpha1.ExperimentStatus) bool {
+	switch status {
+	case v1alpha1.ExperimentStatusCompleted, v1alpha1.ExperimentSkipped, v1alpha1.ExperimentStatusNotFound, ExperimentStatusTimedOut, ExperimentStatusVerdictTimedOut:
+		return true
+	}
+	// the experiments whose chaos pod failed hold the classified reason as status
+	return isPodFailureReason(string(status))
+}
@@ -0,0 +1,194 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResumeExperiments(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+		UID:             "fake-uid",
+		Experiments:     []string{"pod-cpu-hog", "pod-delete", "pod-network-latency", "pod-memory-hog"},
+	}
+	client := CreateFakeClient(t)
+	chaosEngine := &v1alpha1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.Name,
+			Namespace: engineDetails.EngineNamespace,
+		},
+		Status: v1alpha1.ChaosEngineStatus{
+			Experiments: []v1alpha1.ExperimentStatuses{
+				{Name: "pod-cpu-hog", Status: v1alpha1.ExperimentStatusCompleted, Verdict: "Pass"},
+				{Name: "pod-delete", Status: v1alpha1.ExperimentStatusRunning, Verdict: "Awaited"},
+				{Name: "pod-network-latency", Status: v1alpha1.ExperimentStatusWaiting, Verdict: "N/A"},
+				{Name: "pod-memory-hog", Status: v1alpha1.ExperimentStatusRunning, Verdict: "Awaited", ExpPod: "pod-memory-hog-eeeeee-xyz12"},
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("engine not created, err: %v", err)
+	}
+
+	now := time.Now()
+	jobs := []batchv1.Job{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pod-cpu-hog-abcdef",
+				Labels:            map[string]string{"chaosUID": engineDetails.UID, ExperimentLabel: "pod-cpu-hog"},
+				CreationTimestamp: metav1.NewTime(now.Add(-3 * time.Minute)),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pod-delete-aaaaaa",
+				Labels:            map[string]string{"chaosUID": engineDetails.UID, ExperimentLabel: "pod-delete", AttemptLabel: "1"},
+				CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Minute)),
+			},
+		},
+		{
+			// launched by an older chaos-runner, without the experiment label
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pod-delete-bbbbbb",
+				Labels:            map[string]string{"chaosUID": engineDetails.UID, AttemptLabel: "2"},
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pod-network-latency-cccccc",
+				Labels:            map[string]string{"chaosUID": "another-uid", ExperimentLabel: "pod-network-latency"},
+				CreationTimestamp: metav1.NewTime(now),
+			},
+		},
+		{
+			// retained by the jobCleanUpPolicy from an earlier run of the chaosengine
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pod-network-latency-dddddd",
+				Labels:            map[string]string{"chaosUID": engineDetails.UID, ExperimentLabel: "pod-network-latency"},
+				CreationTimestamp: metav1.NewTime(now),
+			},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}},
+			},
+		},
+		{
+			// completed, while its result is yet to be recorded by the previous chaos-runner
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "pod-memory-hog-eeeeee",
+				Labels:            map[string]string{"chaosUID": engineDetails.UID, ExperimentLabel: "pod-memory-hog"},
+				CreationTimestamp: metav1.NewTime(now),
+			},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}},
+			},
+		},
+	}
+	for i := range jobs {
+		jobs[i].Namespace = engineDetails.EngineNamespace
+		if _, err := client.KubeClient.BatchV1().Jobs(engineDetails.EngineNamespace).Create(context.Background(), &jobs[i], metav1.CreateOptions{}); err != nil {
+			t.Fatalf("fail to create exp job, err: %v", err)
+		}
+	}
+
+	experimentList, err := engineDetails.CreateExperimentList()
+	if err != nil {
+		t.Fatalf("fail to create the experiment list, err: %v", err)
+	}
+	if err := engineDetails.ResumeExperiments(experimentList, client); err != nil {
+		t.Fatalf("fail to resume the experiments, err: %v", err)
+	}
+
+	if cpuHog := experimentList[0]; !cpuHog.Finished || cpuHog.Adopted || cpuHog.Verdict != "Pass" {
+		t.Fatalf("expected pod-cpu-hog to be finished with Pass verdict, got finished: %v, adopted: %v, verdict: %v", cpuHog.Finished, cpuHog.Adopted, cpuHog.Verdict)
+	}
+	if podDelete := experimentList[1]; podDelete.Finished || !podDelete.Adopted || podDelete.JobName != "pod-delete-bbbbbb" || podDelete.Attempt != 2 {
+		t.Fatalf("expected the latest job of pod-delete to be adopted, got adopted: %v, job: %v, attempt: %v", podDelete.Adopted, podDelete.JobName, podDelete.Attempt)
+	}
+	if networkLatency := experimentList[2]; networkLatency.Finished || networkLatency.Adopted {
+		t.Fatalf("expected pod-network-latency to be launched again, got finished: %v, adopted: %v", networkLatency.Finished, networkLatency.Adopted)
+	}
+	if memoryHog := experimentList[3]; memoryHog.Finished || !memoryHog.Adopted || memoryHog.JobName != "pod-memory-hog-eeeeee" {
+		t.Fatalf("expected the completed job of pod-memory-hog to be adopted, got adopted: %v, job: %v", memoryHog.Adopted, memoryHog.JobName)
+	}
+
+	if err := InitialPatchEngine(engineDetails, client, experimentList); err != nil {
+		t.Fatalf("fail to patch the initial status, err: %v", err)
+	}
+	chaosEngine, err = client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("fail to get chaosengine after status patch, err: %v", err)
+	}
+	if len(chaosEngine.Status.Experiments) != len(engineDetails.Experiments) {
+		t.Fatalf("expected %v experiment statuses, got %v", len(engineDetails.Experiments), len(chaosEngine.Status.Experiments))
+	}
+	if status := chaosEngine.Status.Experiments[0].Status; status != v1alpha1.ExperimentStatusCompleted {
+		t.Fatalf("expected the status of the finished experiment to be retained, got %v", status)
+	}
+}
+
+func TestInitialPatchEngine(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+		Experiments:     []string{"pod-cpu-hog", "pod-delete"},
+	}
+
+	tests := map[string]struct {
+		experiments []v1alpha1.ExperimentStatuses
+	}{
+		"Test Positive-1": {
+			experiments: nil,
+		},
+		"Test Positive-2": {
+			experiments: []v1alpha1.ExperimentStatuses{
+				{Name: "pod-cpu-hog", Status: ExperimentStatusInterrupted},
+			},
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Status: v1alpha1.ChaosEngineStatus{
+					Experiments: mock.experiments,
+				},
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			experimentList, err := engineDetails.CreateExperimentList()
+			if err != nil {
+				t.Fatalf("fail to create the experiment list for %v test, err: %v", name, err)
+			}
+
+			if err := InitialPatchEngine(engineDetails, client, experimentList); err != nil {
+				t.Fatalf("Test %q failed: fail to patch the initial status, err: %v", name, err)
+			}
+
+			chaosEngine, err = client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("fail to get chaosengine after status patch for %v test, err: %v", name, err)
+			}
+			if len(chaosEngine.Status.Experiments) != len(engineDetails.Experiments) {
+				t.Fatalf("Test %q failed: expected %v experiment statuses, got %v", name, len(engineDetails.Experiments), len(chaosEngine.Status.Experiments))
+			}
+			for _, expStatus := range chaosEngine.Status.Experiments {
+				if expStatus.Status != v1alpha1.ExperimentStatusWaiting {
+					t.Fatalf("Test %q failed: expected experiment status is %v, got %v", name, v1alpha1.ExperimentStatusWaiting, expStatus.Status)
+				}
+			}
+		})
+	}
+}
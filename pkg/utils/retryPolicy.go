@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+const (
+	// RetryAnnotation is the per-experiment chaosengine annotation holding the retry policy of the experiment, e.g.
+	// pod-delete/retry: '{"maxAttempts": 3, "backoff": "10s", "backoffMultiplier": 2, "retryOn": ["JobCreation"]}'
+	RetryAnnotation = "retry"
+	// AttemptsAnnotation is the per-experiment chaosengine annotation holding the number of attempts made for the experiment
+	AttemptsAnnotation = "attempts"
+	// AttemptLabel is the label holding the attempt counter of the experiment job
+	AttemptLabel = "chaosAttempt"
+
+	// JobCreationFailure is the failure class of an experiment whose job couldn't be built or launched
+	JobCreationFailure = "JobCreation"
+	// ChaosContainerWatchFailure is the failure class of an experiment whose chaos container couldn't be watched till completion
+	ChaosContainerWatchFailure = "ChaosContainerWatch"
+)
+
+// RetryPolicy defines how an experiment is retried after a transient failure
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one
+	MaxAttempts int `json:"maxAttempts"`
+	// Backoff is the delay before the second attempt
+	Backoff string `json:"backoff,omitempty"`
+	// BackoffMultiplier is the factor applied to the delay after every attempt
+	BackoffMultiplier float64 `json:"backoffMultiplier,omitempty"`
+	// RetryOn contains the failure classes which are retried, all of them if empty
+	RetryOn []string `json:"retryOn,omitempty"`
+}
+
+// DefaultRetryPolicy runs the experiment only once
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// ParseRetryPolicy parses and validates the retry policy provided inside the chaosengine annotation
+func ParseRetryPolicy(value string) (RetryPolicy, error) {
+	var policy RetryPolicy
+	if err := json.Unmarshal([]byte(value), &policy); err != nil {
+		return DefaultRetryPolicy, errors.Errorf("unable to parse the retry policy, error: %v", err)
+	}
+	if policy.MaxAttempts < 1 {
+		return DefaultRetryPolicy, errors.Errorf("maxAttempts should be at least 1, got %v", policy.MaxAttempts)
+	}
+	if policy.Backoff != "" {
+		if _, err := time.ParseDuration(policy.Backoff); err != nil {
+			return DefaultRetryPolicy, errors.Errorf("unable to parse the backoff, error: %v", err)
+		}
+	}
+	if policy.BackoffMultiplier < 0 {
+		return DefaultRetryPolicy, errors.Errorf("backoffMultiplier should not be negative, got %v", policy.BackoffMultiplier)
+	}
+	return policy, nil
+}
+
+// ShouldRetry checks whether the failed attempt should be followed by another one
+func (policy RetryPolicy) ShouldRetry(failureClass string, attempt int) bool {
+	if attempt >= policy.MaxAttempts {
+		return false
+	}
+	if len(policy.RetryOn) == 0 {
+		return true
+	}
+	for _, class := range policy.RetryOn {
+		if class == failureClass {
+			return true
+		}
+	}
+	return false
+}
+
+// BackoffAfter returns the delay to wait after the given failed attempt
+func (policy RetryPolicy) BackoffAfter(attempt int) time.Duration {
+	backoff, err := time.ParseDuration(policy.Backoff)
+	if err != nil {
+		return 0
+	}
+	multiplier := policy.BackoffMultiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+	return time.Duration(float64(backoff) * math.Pow(multiplier, float64(attempt-1)))
+}
+
+// SetAttempt prepares the experiment for the given attempt, with a fresh job name
+func (expDetails *ExperimentDetails) SetAttempt(attempt int) *ExperimentDetails {
+	expDetails.Attempt = attempt
+	expDetails.JobName = expDetails.Name + "-" + RandomString(6)
+	expDetails.ChaosPodName = ""
+	expDetails.Verdict = ""
+	expDetails.FailStep = ""
+	expDetails.ProbeSuccessPercentage = ""
+	expDetails.Termination = nil
+	expDetails.jobReference = nil
+	expDetails.podReference = nil
+	if expDetails.ExpLabels != nil {
+		expDetails.ExpLabels[AttemptLabel] = strconv.Itoa(attempt)
+	}
+	// the sidecars refer to the main container, which is named after the job
+	for i := range expDetails.SideCars {
+		for j := range expDetails.SideCars[i].ENV {
+			if expDetails.SideCars[i].ENV[j].Name == "MAIN_CONTAINER" {
+				expDetails.SideCars[i].ENV[j] = v1.EnvVar{Name: "MAIN_CONTAINER", Value: expDetails.JobName}
+			}
+		}
+	}
+	return expDetails
+}
+
+// RetryExperiment prepares the next attempt of the experiment after the given failure: it generates the retry event,
+// deletes the job of the failed attempt, if any, and waits for the backoff of the retry policy
+// It returns the cause of the cancellation, if the context is done while waiting
+func (engineDetails EngineDetails) RetryExperiment(ctx context.Context, experiment *ExperimentDetails, failureClass string, clients ClientSets) error {
+	backoff := experiment.RetryPolicy.BackoffAfter(experiment.Attempt)
+	log.WithContext(ctx).Infof("retrying Chaos Experiment: %v after %v failure, attempt: %v/%v, backoff: %v", experiment.Name, failureClass, experiment.Attempt+1, experiment.RetryPolicy.MaxAttempts, backoff)
+	experiment.ExperimentRetried(failureClass, backoff, engineDetails, clients)
+
+	// the job of the failed attempt may still be running, it is deleted to avoid overlapping chaos
+	if err := experiment.DeleteJob(context.Background(), clients); err != nil && !k8serrors.IsNotFound(err) {
+		log.WithContext(ctx).Errorf("unable to delete ChaosExperiment Job name: %v, in namespace: %v, error: %v", experiment.JobName, experiment.Namespace, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-time.After(backoff):
+		return nil
+	}
+}
+
+// RecordAttempts records the number of attempts made for the experiment inside the chaosengine annotations
+func (engineDetails EngineDetails) RecordAttempts(experiment *ExperimentDetails, clients ClientSets) {
+	if experiment.RetryPolicy.MaxAttempts <= 1 {
+		return
+	}
+	annotations := map[string]string{
+		experimentAnnotation(experiment.Name, AttemptsAnnotation): strconv.Itoa(experiment.Attempt),
+	}
+	if err := engineDetails.PatchChaosEngineAnnotations(context.Background(), annotations, clients); err != nil {
+		log.Errorf("unable to record the attempts of Chaos Experiment: %v, error: %v", experiment.Name, err)
+	}
+}
@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseRetryPolicy(t *testing.T) {
+	tests := map[string]struct {
+		value          string
+		expectedPolicy RetryPolicy
+		isErr          bool
+	}{
+		"Test Positive-1": {
+			value: `{"maxAttempts": 3, "backoff": "10s", "backoffMultiplier": 2, "retryOn": ["JobCreation"]}`,
+			expectedPolicy: RetryPolicy{
+				MaxAttempts:       3,
+				Backoff:           "10s",
+				BackoffMultiplier: 2,
+				RetryOn:           []string{JobCreationFailure},
+			},
+			isErr: false,
+		},
+		"Test Positive-2": {
+			value:          `{"maxAttempts": 2}`,
+			expectedPolicy: RetryPolicy{MaxAttempts: 2},
+			isErr:          false,
+		},
+		"Test Negative-1": {
+			value:          `three times`,
+			expectedPolicy: DefaultRetryPolicy,
+			isErr:          true,
+		},
+		"Test Negative-2": {
+			value:          `{"maxAttempts": 0}`,
+			expectedPolicy: DefaultRetryPolicy,
+			isErr:          true,
+		},
+		"Test Negative-3": {
+			value:          `{"maxAttempts": 2, "backoff": "soon"}`,
+			expectedPolicy: DefaultRetryPolicy,
+			isErr:          true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, err := ParseRetryPolicy(mock.value)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			if !reflect.DeepEqual(policy, mock.expectedPolicy) {
+				t.Fatalf("Test %q failed: expected policy is %v, got %v", name, mock.expectedPolicy, policy)
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := map[string]struct {
+		policy       RetryPolicy
+		failureClass string
+		attempt      int
+		isRetried    bool
+	}{
+		"Test Positive-1": {
+			policy:       RetryPolicy{MaxAttempts: 3},
+			failureClass: ChaosContainerWatchFailure,
+			attempt:      2,
+			isRetried:    true,
+		},
+		"Test Positive-2": {
+			policy:       RetryPolicy{MaxAttempts: 3, RetryOn: []string{JobCreationFailure}},
+			failureClass: JobCreationFailure,
+			attempt:      1,
+			isRetried:    true,
+		},
+		"Test Negative-1": {
+			policy:       RetryPolicy{MaxAttempts: 3},
+			failureClass: JobCreationFailure,
+			attempt:      3,
+			isRetried:    false,
+		},
+		"Test Negative-2": {
+			policy:       RetryPolicy{MaxAttempts: 3, RetryOn: []string{JobCreationFailure}},
+			failureClass: ChaosContainerWatchFailure,
+			attempt:      1,
+			isRetried:    false,
+		},
+		"Test Negative-3": {
+			policy:       DefaultRetryPolicy,
+			failureClass: JobCreationFailure,
+			attempt:      1,
+			isRetried:    false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			if isRetried := mock.policy.ShouldRetry(mock.failureClass, mock.attempt); isRetried != mock.isRetried {
+				t.Fatalf("Test %q failed: expected the experiment to be retried: %v, got %v", name, mock.isRetried, isRetried)
+			}
+		})
+	}
+}
+
+func TestBackoffAfter(t *testing.T) {
+	tests := map[string]struct {
+		policy          RetryPolicy
+		attempt         int
+		expectedBackoff time.Duration
+	}{
+		"Test Positive-1": {
+			policy:          RetryPolicy{MaxAttempts: 4, Backoff: "10s", BackoffMultiplier: 2},
+			attempt:         3,
+			expectedBackoff: 40 * time.Second,
+		},
+		"Test Positive-2": {
+			policy:          RetryPolicy{MaxAttempts: 4, Backoff: "10s"},
+			attempt:         3,
+			expectedBackoff: 10 * time.Second,
+		},
+		"Test Positive-3": {
+			policy:          RetryPolicy{MaxAttempts: 4},
+			attempt:         1,
+			expectedBackoff: 0,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			if backoff := mock.policy.BackoffAfter(mock.attempt); backoff != mock.expectedBackoff {
+				t.Fatalf("Test %q failed: expected backoff is %v, got %v", name, mock.expectedBackoff, backoff)
+			}
+		})
+	}
+}
+
+func TestSetAttempt(t *testing.T) {
+	experiment := ExperimentDetails{
+		Name:      "Fake-Exp-Name",
+		JobName:   "Fake-Exp-Name-abcdef",
+		ExpLabels: map[string]string{},
+		Verdict:   "Fail",
+		SideCars: []SideCar{
+			{
+				ENV: []v1.EnvVar{{Name: "MAIN_CONTAINER", Value: "Fake-Exp-Name-abcdef"}},
+			},
+		},
+	}
+	previousJobName := experiment.JobName
+
+	experiment.SetAttempt(2)
+
+	if experiment.Attempt != 2 || experiment.ExpLabels[AttemptLabel] != "2" {
+		t.Fatalf("expected attempt is 2, got %v with label %v", experiment.Attempt, experiment.ExpLabels[AttemptLabel])
+	}
+	if experiment.JobName == previousJobName {
+		t.Fatalf("expected a fresh job name, got %v", experiment.JobName)
+	}
+	if experiment.SideCars[0].ENV[0].Value != experiment.JobName {
+		t.Fatalf("expected the sidecar to refer to %v, got %v", experiment.JobName, experiment.SideCars[0].ENV[0].Value)
+	}
+	if experiment.Verdict != "" {
+		t.Fatalf("expected the verdict to be reset, got %v", experiment.Verdict)
+	}
+}
+
+func TestRetryExperiment(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+
+	tests := map[string]struct {
+		isJobLaunched bool
+		isCancelled   bool
+		isErr         bool
+	}{
+		"Test Positive-1": {
+			isJobLaunched: true,
+			isErr:         false,
+		},
+		"Test Positive-2": {
+			isJobLaunched: false,
+			isErr:         false,
+		},
+		"Test Negative-1": {
+			isJobLaunched: true,
+			isCancelled:   true,
+			isErr:         true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			experiment := ExperimentDetails{
+				Name:        "Fake-Exp-Name",
+				Namespace:   "Fake NameSpace",
+				JobName:     "fake-job-name",
+				Attempt:     1,
+				RetryPolicy: RetryPolicy{MaxAttempts: 2, Backoff: "10ms"},
+			}
+			if mock.isCancelled {
+				experiment.RetryPolicy.Backoff = "1h"
+			}
+			if mock.isJobLaunched {
+				job := &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      experiment.JobName,
+						Namespace: experiment.Namespace,
+					},
+				}
+				if _, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("fail to create exp job for %v test, err: %v", name, err)
+				}
+			}
+
+			ctx, cancel := context.WithCancelCause(context.Background())
+			if mock.isCancelled {
+				cancel(ErrRunnerInterrupted)
+			}
+			defer cancel(nil)
+
+			err := engineDetails.RetryExperiment(ctx, &experiment, JobCreationFailure, client)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			jobList, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil || len(jobList.Items) != 0 {
+				t.Fatalf("Test %q failed: expected the failed job to be deleted, err: %v", name, err)
+			}
+			client.EventRecorder.Flush(EventsFlushTimeout)
+			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil || len(events.Items) != 1 || events.Items[0].Reason != ExperimentRetriedReason {
+				t.Fatalf("Test %q failed: expected %v event to be generated, err: %v", name, ExperimentRetriedReason, err)
+			}
+		})
+	}
+}
+
+func TestRecordAttempts(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+
+	tests := map[string]struct {
+		policy              RetryPolicy
+		expectedAnnotations map[string]string
+	}{
+		"Test Positive-1": {
+			policy:              RetryPolicy{MaxAttempts: 3},
+			expectedAnnotations: map[string]string{"sidecar/enabled": "true", "Fake-Exp-Name/" + AttemptsAnnotation: "2"},
+		},
+		"Test Positive-2": {
+			policy:              DefaultRetryPolicy,
+			expectedAnnotations: map[string]string{"sidecar/enabled": "true"},
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        engineDetails.Name,
+					Namespace:   engineDetails.EngineNamespace,
+					Annotations: map[string]string{"sidecar/enabled": "true"},
+				},
+			}
+			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			experiment := ExperimentDetails{
+				Name:        "Fake-Exp-Name",
+				Attempt:     2,
+				RetryPolicy: mock.policy,
+			}
+
+			engineDetails.RecordAttempts(&experiment, client)
+
+			chaosEngine, err = client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("fail to get chaosengine for %v test, err: %v", name, err)
+			}
+			if !reflect.DeepEqual(chaosEngine.Annotations, mock.expectedAnnotations) {
+				t.Fatalf("Test %q failed: expected annotations are %v, got %v", name, mock.expectedAnnotations, chaosEngine.Annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	litmuschaosv1alpha1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+const (
+	// ParallelismAnnotation is the chaosengine annotation holding the maximum number of experiments executed at once
+	ParallelismAnnotation = "runner/parallelism"
+	// DefaultParallelism runs the experiments sequentially, in the order of the experiment list
+	DefaultParallelism = 1
+	// DependsOnAnnotation is the per-experiment chaosengine annotation holding
+	// the comma-separated list of experiments that should pass before the experiment is started,
+	// e.g. pod-network-latency/depends-on: pod-cpu-hog
+	DependsOnAnnotation = "depends-on"
+	// FailPolicyAnnotation is the chaosengine annotation holding the fail policy of the run
+	FailPolicyAnnotation = "runner/fail-policy"
+)
+
+// FailPolicy decides whether the remaining experiments are executed after an experiment didn't pass
+type FailPolicy string
+
+const (
+	// FailPolicyContinue executes all the experiments, irrespective of the verdicts
+	FailPolicyContinue FailPolicy = "continue"
+	// FailPolicyStopOnFail halts the run once an experiment fails or errors out
+	FailPolicyStopOnFail FailPolicy = "stop-on-fail"
+	// FailPolicyStopOnError halts the run once an experiment errors out,
+	// i.e. it ends with an Error verdict or without any verdict
+	FailPolicyStopOnError FailPolicy = "stop-on-error"
+)
+
+// isValid checks whether the fail policy is a supported one
+func (policy FailPolicy) isValid() bool {
+	switch policy {
+	case FailPolicyContinue, FailPolicyStopOnFail, FailPolicyStopOnError:
+		return true
+	}
+	return false
+}
+
+// HaltsOn checks whether the run should be halted after an experiment finished with the given verdict
+func (policy FailPolicy) HaltsOn(verdict string) bool {
+	isError := verdict == "" || verdict == string(litmuschaosv1alpha1.ResultVerdictError)
+	switch policy {
+	case FailPolicyStopOnFail:
+		return isError || verdict == string(litmuschaosv1alpha1.ResultVerdictFailed)
+	case FailPolicyStopOnError:
+		return isError
+	}
+	return false
+}
+
+// experimentAnnotation returns the chaosengine annotation key of the given experiment
+func experimentAnnotation(expName, key string) string {
+	return expName + "/" + key
+}
+
+// SetRunPolicyFromEngine derives the engine-level run policies from the chaosengine annotations
+func (engineDetails *EngineDetails) SetRunPolicyFromEngine(clients ClientSets) error {
+	chaosEngine, err := engineDetails.GetChaosEngine(context.Background(), clients)
+	if err != nil {
+		return errors.Errorf("unable to get chaosEngine in namespace: %s", engineDetails.EngineNamespace)
+	}
+	engineDetails.SetParallelismFromEngine(chaosEngine).
+		SetDependenciesFromEngine(chaosEngine).
+		SetRetryPoliciesFromEngine(chaosEngine).
+		SetFailPolicyFromEngine(chaosEngine).
+		SetDeadlinesFromEngine(chaosEngine).
+		SetVerdictTimeoutFromEngine(chaosEngine).
+		SetNotifiersFromEngine(chaosEngine, clients)
+	return nil
+}
+
+// SetParallelismFromEngine overrides the parallelism with the one provided in the chaosengine annotations
+func (engineDetails *EngineDetails) SetParallelismFromEngine(engine *litmuschaosv1alpha1.ChaosEngine) *EngineDetails {
+	if value, ok := engine.Annotations[ParallelismAnnotation]; ok {
+		parallelism, err := strconv.Atoi(value)
+		if err != nil {
+			log.Warnf("[skip]: invalid %v annotation value: %v, error: %v", ParallelismAnnotation, value, err)
+		} else {
+			engineDetails.Parallelism = parallelism
+		}
+	}
+	if engineDetails.Parallelism < 1 {
+		engineDetails.Parallelism = DefaultParallelism
+	}
+	return engineDetails
+}
+
+// SetFailPolicyFromEngine overrides the fail policy with the one provided in the chaosengine annotations
+func (engineDetails *EngineDetails) SetFailPolicyFromEngine(engine *litmuschaosv1alpha1.ChaosEngine) *EngineDetails {
+	if value, ok := engine.Annotations[FailPolicyAnnotation]; ok {
+		if policy := FailPolicy(value); policy.isValid() {
+			engineDetails.FailPolicy = policy
+		} else {
+			log.Warnf("[skip]: invalid %v annotation value: %v", FailPolicyAnnotation, value)
+		}
+	}
+	if !engineDetails.FailPolicy.isValid() {
+		engineDetails.FailPolicy = FailPolicyContinue
+	}
+	return engineDetails
+}
+
+// SetDeadlinesFromEngine sets the deadline buffer and the maximum duration of each experiment from the chaosengine annotations
+func (engineDetails *EngineDetails) SetDeadlinesFromEngine(engine *litmuschaosv1alpha1.ChaosEngine) *EngineDetails {
+	engineDetails.DeadlineBuffer = DefaultDeadlineBuffer
+	if value, ok := engine.Annotations[DeadlineBufferAnnotation]; ok {
+		buffer, err := strconv.Atoi(value)
+		if err != nil || buffer < 0 {
+			log.Warnf("[skip]: invalid %v annotation value: %v", DeadlineBufferAnnotation, value)
+		} else {
+			engineDetails.DeadlineBuffer = buffer
+		}
+	}
+
+	engineDetails.MaxDurations = make(map[string]int)
+	for _, expName := range engineDetails.Experiments {
+		value, ok := engine.Annotations[experimentAnnotation(expName, MaxDurationAnnotation)]
+		if !ok {
+			continue
+		}
+		maxDuration, err := strconv.Atoi(value)
+		if err != nil || maxDuration <= 0 {
+			log.Warnf("[skip]: invalid maximum duration for experiment: %v, value: %v", expName, value)
+			continue
+		}
+		engineDetails.MaxDurations[expName] = maxDuration
+	}
+	return engineDetails
+}
+
+// SetDependenciesFromEngine sets the upstream experiments of each experiment from the chaosengine annotations
+func (engineDetails *EngineDetails) SetDependenciesFromEngine(engine *litmuschaosv1alpha1.ChaosEngine) *EngineDetails {
+	engineDetails.Dependencies = make(map[string][]string)
+	for _, expName := range engineDetails.Experiments {
+		value, ok := engine.Annotations[experimentAnnotation(expName, DependsOnAnnotation)]
+		if !ok {
+			continue
+		}
+		for _, upstream := range strings.Split(value, ",") {
+			if upstream = strings.TrimSpace(upstream); upstream != "" {
+				engineDetails.Dependencies[expName] = append(engineDetails.Dependencies[expName], upstream)
+			}
+		}
+	}
+	return engineDetails
+}
+
+// SetRetryPoliciesFromEngine sets the retry policy of each experiment from the chaosengine annotations
+func (engineDetails *EngineDetails) SetRetryPoliciesFromEngine(engine *litmuschaosv1alpha1.ChaosEngine) *EngineDetails {
+	engineDetails.RetryPolicies = make(map[string]RetryPolicy)
+	for _, expName := range engineDetails.Experiments {
+		value, ok := engine.Annotations[experimentAnnotation(expName, RetryAnnotation)]
+		if !ok {
+			continue
+		}
+		policy, err := ParseRetryPolicy(value)
+		if err != nil {
+			log.Warnf("[skip]: invalid retry policy for experiment: %v, error: %v", expName, err)
+			continue
+		}
+		engineDetails.RetryPolicies[expName] = policy
+	}
+	return engineDetails
+}
@@ -0,0 +1,290 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetParallelismFromEngine(t *testing.T) {
+	tests := map[string]struct {
+		parallelism         int
+		annotations         map[string]string
+		expectedParallelism int
+	}{
+		"Test Positive-1": {
+			parallelism:         1,
+			annotations:         map[string]string{ParallelismAnnotation: "3"},
+			expectedParallelism: 3,
+		},
+		"Test Positive-2": {
+			parallelism:         2,
+			annotations:         nil,
+			expectedParallelism: 2,
+		},
+		"Test Negative-1": {
+			parallelism:         2,
+			annotations:         map[string]string{ParallelismAnnotation: "many"},
+			expectedParallelism: 2,
+		},
+		"Test Negative-2": {
+			parallelism:         0,
+			annotations:         map[string]string{ParallelismAnnotation: "-1"},
+			expectedParallelism: DefaultParallelism,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:        "Fake Engine",
+				Parallelism: mock.parallelism,
+			}
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        engineDetails.Name,
+					Annotations: mock.annotations,
+				},
+			}
+			engineDetails.SetParallelismFromEngine(chaosEngine)
+			if engineDetails.Parallelism != mock.expectedParallelism {
+				t.Fatalf("Test %q failed: expected parallelism is %v, got %v", name, mock.expectedParallelism, engineDetails.Parallelism)
+			}
+		})
+	}
+}
+
+func TestSetDependenciesFromEngine(t *testing.T) {
+	tests := map[string]struct {
+		experiments          []string
+		annotations          map[string]string
+		expectedDependencies map[string][]string
+	}{
+		"Test Positive-1": {
+			experiments: []string{"pod-cpu-hog", "pod-network-latency", "pod-delete"},
+			annotations: map[string]string{
+				"pod-network-latency/" + DependsOnAnnotation: "pod-cpu-hog",
+				"pod-delete/" + DependsOnAnnotation:          "pod-cpu-hog, pod-network-latency",
+			},
+			expectedDependencies: map[string][]string{
+				"pod-network-latency": {"pod-cpu-hog"},
+				"pod-delete":          {"pod-cpu-hog", "pod-network-latency"},
+			},
+		},
+		"Test Positive-2": {
+			experiments:          []string{"pod-cpu-hog"},
+			annotations:          nil,
+			expectedDependencies: map[string][]string{},
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:        "Fake Engine",
+				Experiments: mock.experiments,
+			}
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        engineDetails.Name,
+					Annotations: mock.annotations,
+				},
+			}
+			engineDetails.SetDependenciesFromEngine(chaosEngine)
+			if !reflect.DeepEqual(engineDetails.Dependencies, mock.expectedDependencies) {
+				t.Fatalf("Test %q failed: expected dependencies are %v, got %v", name, mock.expectedDependencies, engineDetails.Dependencies)
+			}
+		})
+	}
+}
+
+func TestSetRetryPoliciesFromEngine(t *testing.T) {
+	tests := map[string]struct {
+		experiments      []string
+		annotations      map[string]string
+		expectedPolicies map[string]RetryPolicy
+	}{
+		"Test Positive-1": {
+			experiments: []string{"pod-cpu-hog", "pod-delete"},
+			annotations: map[string]string{
+				"pod-delete/" + RetryAnnotation: `{"maxAttempts": 3, "backoff": "5s"}`,
+			},
+			expectedPolicies: map[string]RetryPolicy{
+				"pod-delete": {MaxAttempts: 3, Backoff: "5s"},
+			},
+		},
+		"Test Negative-1": {
+			experiments: []string{"pod-delete"},
+			annotations: map[string]string{
+				"pod-delete/" + RetryAnnotation: `{"maxAttempts": -1}`,
+			},
+			expectedPolicies: map[string]RetryPolicy{},
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:        "Fake Engine",
+				Experiments: mock.experiments,
+			}
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        engineDetails.Name,
+					Annotations: mock.annotations,
+				},
+			}
+			engineDetails.SetRetryPoliciesFromEngine(chaosEngine)
+			if !reflect.DeepEqual(engineDetails.RetryPolicies, mock.expectedPolicies) {
+				t.Fatalf("Test %q failed: expected retry policies are %v, got %v", name, mock.expectedPolicies, engineDetails.RetryPolicies)
+			}
+		})
+	}
+}
+
+func TestSetFailPolicyFromEngine(t *testing.T) {
+	tests := map[string]struct {
+		failPolicy         FailPolicy
+		annotations        map[string]string
+		expectedFailPolicy FailPolicy
+	}{
+		"Test Positive-1": {
+			failPolicy:         "",
+			annotations:        map[string]string{FailPolicyAnnotation: "stop-on-fail"},
+			expectedFailPolicy: FailPolicyStopOnFail,
+		},
+		"Test Positive-2": {
+			failPolicy:         FailPolicyStopOnError,
+			annotations:        nil,
+			expectedFailPolicy: FailPolicyStopOnError,
+		},
+		"Test Negative-1": {
+			failPolicy:         FailPolicyStopOnError,
+			annotations:        map[string]string{FailPolicyAnnotation: "stop-on-everything"},
+			expectedFailPolicy: FailPolicyStopOnError,
+		},
+		"Test Negative-2": {
+			failPolicy:         "stop-sometimes",
+			annotations:        nil,
+			expectedFailPolicy: FailPolicyContinue,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:       "Fake Engine",
+				FailPolicy: mock.failPolicy,
+			}
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        engineDetails.Name,
+					Annotations: mock.annotations,
+				},
+			}
+			engineDetails.SetFailPolicyFromEngine(chaosEngine)
+			if engineDetails.FailPolicy != mock.expectedFailPolicy {
+				t.Fatalf("Test %q failed: expected fail policy is %v, got %v", name, mock.expectedFailPolicy, engineDetails.FailPolicy)
+			}
+		})
+	}
+}
+
+func TestFailPolicyHaltsOn(t *testing.T) {
+	tests := map[string]struct {
+		failPolicy FailPolicy
+		verdict    string
+		isHalted   bool
+	}{
+		"Test Positive-1": {
+			failPolicy: FailPolicyStopOnFail,
+			verdict:    "Fail",
+			isHalted:   true,
+		},
+		"Test Positive-2": {
+			failPolicy: FailPolicyStopOnFail,
+			verdict:    "Error",
+			isHalted:   true,
+		},
+		"Test Positive-3": {
+			failPolicy: FailPolicyStopOnError,
+			verdict:    "",
+			isHalted:   true,
+		},
+		"Test Negative-1": {
+			failPolicy: FailPolicyStopOnError,
+			verdict:    "Fail",
+			isHalted:   false,
+		},
+		"Test Negative-2": {
+			failPolicy: FailPolicyContinue,
+			verdict:    "Error",
+			isHalted:   false,
+		},
+		"Test Negative-3": {
+			failPolicy: FailPolicyStopOnFail,
+			verdict:    "Pass",
+			isHalted:   false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			if isHalted := mock.failPolicy.HaltsOn(mock.verdict); isHalted != mock.isHalted {
+				t.Fatalf("Test %q failed: expected the run to be halted: %v, got %v", name, mock.isHalted, isHalted)
+			}
+		})
+	}
+}
+
+func TestSetDeadlinesFromEngine(t *testing.T) {
+	tests := map[string]struct {
+		experiments            []string
+		annotations            map[string]string
+		expectedDeadlineBuffer int
+		expectedMaxDurations   map[string]int
+	}{
+		"Test Positive-1": {
+			experiments: []string{"pod-cpu-hog", "pod-delete"},
+			annotations: map[string]string{
+				DeadlineBufferAnnotation:              "120",
+				"pod-delete/" + MaxDurationAnnotation: "300",
+			},
+			expectedDeadlineBuffer: 120,
+			expectedMaxDurations:   map[string]int{"pod-delete": 300},
+		},
+		"Test Positive-2": {
+			experiments:            []string{"pod-delete"},
+			annotations:            nil,
+			expectedDeadlineBuffer: DefaultDeadlineBuffer,
+			expectedMaxDurations:   map[string]int{},
+		},
+		"Test Negative-1": {
+			experiments: []string{"pod-delete"},
+			annotations: map[string]string{
+				DeadlineBufferAnnotation:              "-1",
+				"pod-delete/" + MaxDurationAnnotation: "a while",
+			},
+			expectedDeadlineBuffer: DefaultDeadlineBuffer,
+			expectedMaxDurations:   map[string]int{},
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:        "Fake Engine",
+				Experiments: mock.experiments,
+			}
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        engineDetails.Name,
+					Annotations: mock.annotations,
+				},
+			}
+			engineDetails.SetDeadlinesFromEngine(chaosEngine)
+			if engineDetails.DeadlineBuffer != mock.expectedDeadlineBuffer {
+				t.Fatalf("Test %q failed: expected deadline buffer is %v, got %v", name, mock.expectedDeadlineBuffer, engineDetails.DeadlineBuffer)
+			}
+			if !reflect.DeepEqual(engineDetails.MaxDurations, mock.expectedMaxDurations) {
+				t.Fatalf("Test %q failed: expected maximum durations are %v, got %v", name, mock.expectedMaxDurations, engineDetails.MaxDurations)
+			}
+		})
+	}
+}
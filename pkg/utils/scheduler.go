@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+)
+
+// RunExperiments executes the lifecycle of every experiment in the list,
+// keeping at most engineDetails.Parallelism experiments in flight at once.
+// An experiment is started only after all its upstream experiments passed,
+// and is skipped if any of them didn't. Experiments which are ready at the
+// same time are started in the order of the list, so with a parallelism of 1
+// and no dependencies, the experiments run in the order of the list.
+// Once the context is done, or an experiment halts the run as per the fail policy,
+// no more experiments are started, and the ones which were never started are returned
+// after the running ones finished, along with the experiment which halted the run, if any
+func (engineDetails EngineDetails) RunExperiments(ctx context.Context, experimentList []ExperimentDetails, runExperiment func(context.Context, *ExperimentDetails), skipExperiment func(context.Context, *ExperimentDetails, string)) ([]*ExperimentDetails, *ExperimentDetails) {
+	parallelism := engineDetails.Parallelism
+	if parallelism < 1 {
+		parallelism = DefaultParallelism
+	}
+
+	// passed holds the outcome of every finished experiment
+	passed := make(map[string]bool, len(experimentList))
+	pending := make([]*ExperimentDetails, 0, len(experimentList))
+	for i := range experimentList {
+		pending = append(pending, &experimentList[i])
+	}
+	completed := make(chan *ExperimentDetails)
+	running := 0
+	// haltedBy is the first experiment whose verdict halted the run as per the fail policy
+	var haltedBy *ExperimentDetails
+
+	for len(pending) != 0 || running != 0 {
+		// keep scanning, as a skipped experiment may unblock its own downstream experiments
+		for progressed := true; progressed && ctx.Err() == nil && haltedBy == nil; {
+			progressed = false
+			var waiting []*ExperimentDetails
+			for _, experiment := range pending {
+				ready, failedUpstreams := experiment.upstreamState(passed)
+				switch {
+				case len(failedUpstreams) != 0:
+					skipExperiment(ctx, experiment, strings.Join(failedUpstreams, ","))
+					passed[experiment.Name] = false
+					progressed = true
+				case ready && running < parallelism:
+					running++
+					go func(experiment *ExperimentDetails) {
+						runExperiment(ctx, experiment)
+						completed <- experiment
+					}(experiment)
+					progressed = true
+				default:
+					waiting = append(waiting, experiment)
+				}
+			}
+			pending = waiting
+		}
+		if running == 0 {
+			// nothing can be started anymore, either because the context is done,
+			// the run is halted or because of unresolved dependencies
+			return pending, haltedBy
+		}
+
+		experiment := <-completed
+		running--
+		passed[experiment.Name] = experiment.Verdict == string(v1alpha1.ResultVerdictPassed)
+		if haltedBy == nil && ctx.Err() == nil && engineDetails.FailPolicy.HaltsOn(experiment.Verdict) {
+			haltedBy = experiment
+		}
+	}
+	return nil, haltedBy
+}
+
+// upstreamState returns whether all the upstream experiments passed,
+// along with the upstream experiments which finished without passing
+func (expDetails *ExperimentDetails) upstreamState(passed map[string]bool) (bool, []string) {
+	ready := true
+	var failedUpstreams []string
+	for _, upstream := range expDetails.DependsOn {
+		upstreamPassed, finished := passed[upstream]
+		switch {
+		case !finished:
+			ready = false
+		case !upstreamPassed:
+			failedUpstreams = append(failedUpstreams, upstream)
+		}
+	}
+	return ready && len(failedUpstreams) == 0, failedUpstreams
+}
@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunExperiments(t *testing.T) {
+	tests := map[string]struct {
+		parallelism   int
+		experiments   []string
+		maxConcurrent int
+	}{
+		"Test Positive-1": {
+			parallelism:   1,
+			experiments:   []string{"exp-1", "exp-2", "exp-3"},
+			maxConcurrent: 1,
+		},
+		"Test Positive-2": {
+			parallelism:   2,
+			experiments:   []string{"exp-1", "exp-2", "exp-3", "exp-4", "exp-5"},
+			maxConcurrent: 2,
+		},
+		"Test Positive-3": {
+			parallelism:   0,
+			experiments:   []string{"exp-1", "exp-2"},
+			maxConcurrent: 1,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:        "Fake Engine",
+				Experiments: mock.experiments,
+				Parallelism: mock.parallelism,
+			}
+			experimentList, err := engineDetails.CreateExperimentList()
+			if err != nil {
+				t.Fatalf("fail to create the experiment list for %v test, err: %v", name, err)
+			}
+
+			var lock sync.Mutex
+			var inFlight, maxInFlight int
+			var executed []string
+			engineDetails.RunExperiments(context.Background(), experimentList, func(ctx context.Context, experiment *ExperimentDetails) {
+				lock.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				executed = append(executed, experiment.Name)
+				lock.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				lock.Lock()
+				inFlight--
+				lock.Unlock()
+			}, func(ctx context.Context, experiment *ExperimentDetails, upstreams string) {
+				t.Errorf("Test %q failed: experiment %v is skipped", name, experiment.Name)
+			})
+
+			if len(executed) != len(mock.experiments) {
+				t.Fatalf("Test %q failed: expected %v experiments to be executed, got %v", name, len(mock.experiments), len(executed))
+			}
+			if maxInFlight > mock.maxConcurrent {
+				t.Fatalf("Test %q failed: expected at most %v experiments in flight, got %v", name, mock.maxConcurrent, maxInFlight)
+			}
+			if mock.maxConcurrent == 1 && !reflect.DeepEqual(executed, mock.experiments) {
+				t.Fatalf("Test %q failed: expected the experiments to be executed in order %v, got %v", name, mock.experiments, executed)
+			}
+		})
+	}
+}
+
+func TestRunExperimentsWithDependencies(t *testing.T) {
+	tests := map[string]struct {
+		parallelism  int
+		experiments  []string
+		dependencies map[string][]string
+		verdicts     map[string]string
+		executed     []string
+		skipped      []string
+	}{
+		"Test Positive-1": {
+			parallelism: 1,
+			experiments: []string{"pod-network-latency", "pod-cpu-hog", "pod-delete"},
+			dependencies: map[string][]string{
+				"pod-network-latency": {"pod-cpu-hog"},
+			},
+			verdicts: map[string]string{
+				"pod-cpu-hog":         "Pass",
+				"pod-delete":          "Pass",
+				"pod-network-latency": "Pass",
+			},
+			executed: []string{"pod-cpu-hog", "pod-network-latency", "pod-delete"},
+		},
+		"Test Positive-2": {
+			parallelism: 2,
+			experiments: []string{"pod-cpu-hog", "pod-network-latency", "pod-memory-hog", "pod-delete"},
+			dependencies: map[string][]string{
+				"pod-network-latency": {"pod-cpu-hog"},
+				"pod-delete":          {"pod-network-latency"},
+			},
+			verdicts: map[string]string{
+				"pod-cpu-hog":    "Fail",
+				"pod-memory-hog": "Pass",
+			},
+			executed: []string{"pod-cpu-hog", "pod-memory-hog"},
+			skipped:  []string{"pod-network-latency", "pod-delete"},
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:         "Fake Engine",
+				Experiments:  mock.experiments,
+				Parallelism:  mock.parallelism,
+				Dependencies: mock.dependencies,
+			}
+			experimentList, err := engineDetails.CreateExperimentList()
+			if err != nil {
+				t.Fatalf("fail to create the experiment list for %v test, err: %v", name, err)
+			}
+
+			var lock sync.Mutex
+			var executed, skipped []string
+			engineDetails.RunExperiments(context.Background(), experimentList, func(ctx context.Context, experiment *ExperimentDetails) {
+				lock.Lock()
+				defer lock.Unlock()
+				executed = append(executed, experiment.Name)
+				experiment.Verdict = mock.verdicts[experiment.Name]
+			}, func(ctx context.Context, experiment *ExperimentDetails, upstreams string) {
+				skipped = append(skipped, experiment.Name)
+			})
+
+			if len(executed) != len(mock.executed) {
+				t.Fatalf("Test %q failed: expected %v to be executed, got %v", name, mock.executed, executed)
+			}
+			if mock.parallelism == 1 && !reflect.DeepEqual(executed, mock.executed) {
+				t.Fatalf("Test %q failed: expected the experiments to be executed in order %v, got %v", name, mock.executed, executed)
+			}
+			if !reflect.DeepEqual(skipped, mock.skipped) {
+				t.Fatalf("Test %q failed: expected %v to be skipped, got %v", name, mock.skipped, skipped)
+			}
+		})
+	}
+}
+
+func TestRunExperimentsWithCancelledContext(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:        "Fake Engine",
+		Experiments: []string{"exp-1", "exp-2", "exp-3"},
+		Parallelism: 1,
+	}
+	experimentList, err := engineDetails.CreateExperimentList()
+	if err != nil {
+		t.Fatalf("fail to create the experiment list, err: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var executed []string
+	notStarted, _ := engineDetails.RunExperiments(ctx, experimentList, func(ctx context.Context, experiment *ExperimentDetails) {
+		executed = append(executed, experiment.Name)
+		// stop the run while the first experiment is in flight
+		cancel()
+	}, func(ctx context.Context, experiment *ExperimentDetails, upstreams string) {
+		t.Errorf("experiment %v is skipped", experiment.Name)
+	})
+
+	if !reflect.DeepEqual(executed, []string{"exp-1"}) {
+		t.Fatalf("expected only exp-1 to be executed, got %v", executed)
+	}
+	var notStartedNames []string
+	for _, experiment := range notStarted {
+		notStartedNames = append(notStartedNames, experiment.Name)
+	}
+	if !reflect.DeepEqual(notStartedNames, []string{"exp-2", "exp-3"}) {
+		t.Fatalf("expected exp-2 and exp-3 not to be started, got %v", notStartedNames)
+	}
+}
+
+func TestRunExperimentsWithFailPolicy(t *testing.T) {
+	tests := map[string]struct {
+		failPolicy FailPolicy
+		verdicts   map[string]string
+		executed   []string
+		notStarted []string
+		haltedBy   string
+	}{
+		"Test Positive-1": {
+			failPolicy: FailPolicyContinue,
+			verdicts:   map[string]string{"exp-1": "Fail", "exp-2": "Error", "exp-3": "Pass"},
+			executed:   []string{"exp-1", "exp-2", "exp-3"},
+		},
+		"Test Positive-2": {
+			failPolicy: FailPolicyStopOnFail,
+			verdicts:   map[string]string{"exp-1": "Pass", "exp-2": "Fail", "exp-3": "Pass"},
+			executed:   []string{"exp-1", "exp-2"},
+			notStarted: []string{"exp-3"},
+			haltedBy:   "exp-2",
+		},
+		"Test Positive-3": {
+			failPolicy: FailPolicyStopOnError,
+			verdicts:   map[string]string{"exp-1": "Fail", "exp-2": "", "exp-3": "Pass"},
+			executed:   []string{"exp-1", "exp-2"},
+			notStarted: []string{"exp-3"},
+			haltedBy:   "exp-2",
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:        "Fake Engine",
+				Experiments: []string{"exp-1", "exp-2", "exp-3"},
+				Parallelism: 1,
+				FailPolicy:  mock.failPolicy,
+			}
+			experimentList, err := engineDetails.CreateExperimentList()
+			if err != nil {
+				t.Fatalf("fail to create the experiment list for %v test, err: %v", name, err)
+			}
+
+			var executed []string
+			notStarted, haltedBy := engineDetails.RunExperiments(context.Background(), experimentList, func(ctx context.Context, experiment *ExperimentDetails) {
+				executed = append(executed, experiment.Name)
+				experiment.Verdict = mock.verdicts[experiment.Name]
+			}, func(ctx context.Context, experiment *ExperimentDetails, upstreams string) {
+				t.Errorf("Test %q failed: experiment %v is skipped", name, experiment.Name)
+			})
+
+			if !reflect.DeepEqual(executed, mock.executed) {
+				t.Fatalf("Test %q failed: expected %v to be executed, got %v", name, mock.executed, executed)
+			}
+			var notStartedNames []string
+			for _, experiment := range notStarted {
+				notStartedNames = append(notStartedNames, experiment.Name)
+			}
+			if !reflect.DeepEqual(notStartedNames, mock.notStarted) {
+				t.Fatalf("Test %q failed: expected %v not to be started, got %v", name, mock.notStarted, notStartedNames)
+			}
+			var haltedByName string
+			if haltedBy != nil {
+				haltedByName = haltedBy.Name
+			}
+			if haltedByName != mock.haltedBy {
+				t.Fatalf("Test %q failed: expected the run to be halted by %q, got %q", name, mock.haltedBy, haltedByName)
+			}
+		})
+	}
+}
@@ -118,6 +118,8 @@ func TestPatchSecrets(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			_, err := client.KubeClient.CoreV1().Secrets(experiment.Namespace).Create(context.Background(), &mock.secret, metav1.CreateOptions{})
 			if err != nil {
@@ -237,6 +239,8 @@ func TestSetSecrets(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			if !mock.isErr {
 				_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
@@ -415,6 +419,8 @@ func TestGetSecretsFromChaosExperiment(t *testing.T) {
 	for name, mock := range tests {
 		t.Run(name, func(t *testing.T) {
 			client := CreateFakeClient(t)
+			// every test case runs against its own cluster, hence the snapshot is taken again
+			experiment.Snapshot = ResourceSnapshot{}
 
 			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(mock.chaosexperiment.Namespace).Create(context.Background(), mock.chaosexperiment, metav1.CreateOptions{})
 			if err != nil {
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// ErrRunnerInterrupted is the cause of the cancellation of the run, once the runner pod is asked to terminate
+var ErrRunnerInterrupted = errors.New("chaos-runner is interrupted")
+
+// The chaos-runner exits within InterruptTimeout + InterruptExitTimeout (25s) since the termination signal,
+// so that it fits into the default termination grace period of 30s of the runner pod
+const (
+	// InterruptTimeout bounds the clean up of the halted experiments, counted from the termination signal
+	InterruptTimeout = 15 * time.Second
+	// InterruptExitTimeout bounds the steps after the clean up, counted from its deadline, i.e. the run summary,
+	// the drain of the notifications, the flush of the events and the shutdown of the metrics and the telemetry
+	InterruptExitTimeout = 10 * time.Second
+)
+
+// runnerInterruption is the cause of the cancellation of the run on the termination signal,
+// which carries the deadline of the clean up shared by all the halted experiments
+type runnerInterruption struct {
+	deadline time.Time
+}
+
+func (interruption *runnerInterruption) Error() string {
+	return ErrRunnerInterrupted.Error()
+}
+
+// Is matches the interruption with ErrRunnerInterrupted
+func (interruption *runnerInterruption) Is(target error) bool {
+	return target == ErrRunnerInterrupted
+}
+
+// InterruptContext returns the context of the clean up of the interrupted run, which is done once the InterruptTimeout
+// since the termination signal is reached, or since now if the cause doesn't carry the time of the signal
+func InterruptContext(cause error) (context.Context, context.CancelFunc) {
+	deadline := time.Now().Add(InterruptTimeout)
+	var interruption *runnerInterruption
+	if errors.As(cause, &interruption) {
+		deadline = interruption.deadline
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// ExitContext returns the context of the steps run before the chaos-runner exits, e.g. the run summary,
+// which is done at the exit deadline once the run is interrupted, and is never done otherwise
+// It carries the values of the given run context, e.g. its span
+func ExitContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	var interruption *runnerInterruption
+	if errors.As(context.Cause(ctx), &interruption) {
+		return context.WithDeadline(context.WithoutCancel(ctx), interruption.deadline.Add(InterruptExitTimeout))
+	}
+	return context.WithCancel(context.WithoutCancel(ctx))
+}
+
+// ExitTimeout returns the given timeout of a step run before the chaos-runner exits,
+// bounded by the time left till the exit deadline once the run is interrupted
+func ExitTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	exitCtx, cancel := ExitContext(ctx)
+	defer cancel()
+	if deadline, ok := exitCtx.Deadline(); ok {
+		return max(min(timeout, time.Until(deadline)), 0)
+	}
+	return timeout
+}
+
+// NotifyOnTermination returns a copy of the parent context, which is cancelled
+// with ErrRunnerInterrupted once the runner receives SIGTERM or SIGINT.
+// Only the first signal is handled, any further signal terminates the runner right away
+func NotifyOnTermination(parent context.Context) (context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			log.Warnf("received %v signal, interrupting the chaos-runner", sig)
+			cancel(&runnerInterruption{deadline: time.Now().Add(InterruptTimeout)})
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// HaltExperiment stops the experiment according to the cause of the cancellation of the run
+// It returns false if the cause doesn't require the experiment to be halted
+func (engineDetails EngineDetails) HaltExperiment(cause error, experiment *ExperimentDetails, clients ClientSets) bool {
+	switch {
+	case errors.Is(cause, ErrEngineStopped):
+		engineDetails.AbortExperiment(experiment, clients)
+	case errors.Is(cause, ErrRunnerInterrupted):
+		ctx, cancel := InterruptContext(cause)
+		defer cancel()
+		engineDetails.InterruptExperiment(ctx, experiment, clients)
+	default:
+		return false
+	}
+	return true
+}
+
+// InterruptExperiment cleans up the experiment job, if already launched, according to the jobCleanUpPolicy
+// and marks the experiment as interrupted, till the context is done
+func (engineDetails EngineDetails) InterruptExperiment(ctx context.Context, experiment *ExperimentDetails, clients ClientSets) {
+	log.Infof("chaos-runner is interrupted, halting Chaos Experiment: %v", experiment.Name)
+	engineDetails.haltExperiment(ctx, experiment, func(expStatus *ExperimentStatus, chaosPodName string) {
+		expStatus.InterruptedExperimentStatus(experiment.Name, engineDetails.Name, chaosPodName)
+	}, jobCleanUpByPolicy, clients)
+}
+
+// jobCleanUp decides how the experiment job of the halted experiment is cleaned up
+type jobCleanUp int
+
+const (
+	// jobCleanUpByPolicy deletes or retains the experiment job, according to the jobCleanUpPolicy of the chaosengine
+	jobCleanUpByPolicy jobCleanUp = iota
+	// jobCleanUpAlways deletes the experiment job regardless of the jobCleanUpPolicy
+	jobCleanUpAlways
+)
+
+// haltExperiment marks the halted experiment with the status set by setStatus, along with the name of its chaos pod if known,
+// and cleans up its experiment job, if already launched. It is shared by all the ways an experiment is halted
+func (engineDetails EngineDetails) haltExperiment(ctx context.Context, experiment *ExperimentDetails, setStatus func(expStatus *ExperimentStatus, chaosPodName string),
+	cleanUp jobCleanUp, clients ClientSets) {
+	chaosPodName := experiment.ChaosPodName
+	if chaosPodName == "" {
+		chaosPodName = "N/A"
+	}
+	var expStatus ExperimentStatus
+	setStatus(&expStatus, chaosPodName)
+	if err := expStatus.PatchChaosEngineStatus(ctx, engineDetails, clients); err != nil {
+		log.Errorf("unable to Patch ChaosEngine with Status, error: %v", err)
+	}
+
+	if _, err := clients.KubeClient.BatchV1().Jobs(experiment.Namespace).Get(ctx, experiment.JobName, metav1.GetOptions{}); err != nil {
+		return
+	}
+	if cleanUp == jobCleanUpAlways {
+		if err := experiment.DeleteJob(ctx, clients); err != nil && !k8serrors.IsNotFound(err) {
+			log.Errorf("unable to delete ChaosExperiment Job name: %v, in namespace: %v, error: %v", experiment.JobName, experiment.Namespace, err)
+		}
+		return
+	}
+	jobCleanUpPolicy, err := engineDetails.DeleteJobAccordingToJobCleanUpPolicy(ctx, experiment, clients)
+	if err != nil {
+		log.Errorf("unable to Delete ChaosExperiment Job, error: %v", err)
+	}
+	experiment.ExperimentJobCleanUp(string(jobCleanUpPolicy), engineDetails, clients)
+}
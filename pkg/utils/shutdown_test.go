@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNotifyOnTermination(t *testing.T) {
+	ctx, cancel := NotifyOnTermination(context.Background())
+	defer cancel(nil)
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("fail to send the signal, err: %v", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("context is not cancelled after the signal")
+	}
+	if !errors.Is(context.Cause(ctx), ErrRunnerInterrupted) {
+		t.Fatalf("expected the cause of the cancellation to be %v, got %v", ErrRunnerInterrupted, context.Cause(ctx))
+	}
+}
+
+func TestInterruptContext(t *testing.T) {
+	signalled := time.Now().Add(-InterruptTimeout / 2)
+	tests := map[string]struct {
+		cause            error
+		expectedDeadline time.Time
+	}{
+		"Test Positive-1": {
+			cause:            &runnerInterruption{deadline: signalled.Add(InterruptTimeout)},
+			expectedDeadline: signalled.Add(InterruptTimeout),
+		},
+		"Test Positive-2": {
+			cause:            ErrRunnerInterrupted,
+			expectedDeadline: time.Now().Add(InterruptTimeout),
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := InterruptContext(mock.cause)
+			defer cancel()
+			deadline, ok := ctx.Deadline()
+			if !ok || deadline.Sub(mock.expectedDeadline).Abs() > time.Second {
+				t.Fatalf("Test %q failed: expected the deadline to be %v, got %v", name, mock.expectedDeadline, deadline)
+			}
+		})
+	}
+}
+
+func TestExitTimeout(t *testing.T) {
+	signalled := time.Now().Add(-InterruptTimeout)
+	tests := map[string]struct {
+		cause           error
+		timeout         time.Duration
+		expectedTimeout time.Duration
+	}{
+		"Test Positive-1": {
+			cause:           nil,
+			timeout:         EventsFlushTimeout,
+			expectedTimeout: EventsFlushTimeout,
+		},
+		"Test Positive-2": {
+			cause:           &runnerInterruption{deadline: signalled.Add(InterruptTimeout)},
+			timeout:         EventsFlushTimeout,
+			expectedTimeout: EventsFlushTimeout,
+		},
+		"Test Positive-3": {
+			cause:           &runnerInterruption{deadline: signalled.Add(InterruptTimeout - InterruptExitTimeout + 2*time.Second)},
+			timeout:         EventsFlushTimeout,
+			expectedTimeout: 2 * time.Second,
+		},
+		"Test Negative-1": {
+			cause:           &runnerInterruption{deadline: signalled.Add(-InterruptExitTimeout)},
+			timeout:         EventsFlushTimeout,
+			expectedTimeout: 0,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+			if mock.cause != nil {
+				cancel(mock.cause)
+			}
+			if timeout := ExitTimeout(ctx, mock.timeout); (timeout - mock.expectedTimeout).Abs() > time.Second {
+				t.Fatalf("Test %q failed: expected the timeout to be %v, got %v", name, mock.expectedTimeout, timeout)
+			}
+		})
+	}
+}
+
+func TestInterruptExperiment(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	experiment := ExperimentDetails{
+		Name:      "Fake-Exp-Name",
+		Namespace: "Fake NameSpace",
+		JobName:   "fake-job-name",
+	}
+
+	tests := map[string]struct {
+		jobCleanUpPolicy v1alpha1.CleanUpPolicy
+		isJobLaunched    bool
+		isJobRetained    bool
+	}{
+		"Test Positive-1": {
+			jobCleanUpPolicy: v1alpha1.CleanUpPolicyDelete,
+			isJobLaunched:    true,
+			isJobRetained:    false,
+		},
+		"Test Positive-2": {
+			jobCleanUpPolicy: v1alpha1.CleanUpPolicyRetain,
+			isJobLaunched:    true,
+			isJobRetained:    true,
+		},
+		"Test Positive-3": {
+			jobCleanUpPolicy: v1alpha1.CleanUpPolicyDelete,
+			isJobLaunched:    false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Spec: v1alpha1.ChaosEngineSpec{
+					JobCleanUpPolicy: mock.jobCleanUpPolicy,
+				},
+				Status: v1alpha1.ChaosEngineStatus{
+					Experiments: []v1alpha1.ExperimentStatuses{
+						{
+							Name:   experiment.Name,
+							Status: v1alpha1.ExperimentStatusRunning,
+						},
+					},
+				},
+			}
+			_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			if mock.isJobLaunched {
+				job := &batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      experiment.JobName,
+						Namespace: experiment.Namespace,
+					},
+				}
+				if _, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("fail to create exp job for %v test, err: %v", name, err)
+				}
+			}
+
+			if !engineDetails.HaltExperiment(ErrRunnerInterrupted, &experiment, client) {
+				t.Fatalf("Test %q failed: expected the experiment to be halted", name)
+			}
+
+			jobList, err := client.KubeClient.BatchV1().Jobs(experiment.Namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("fail to list the exp jobs for %v test, err: %v", name, err)
+			}
+			if isJobRetained := len(jobList.Items) != 0; isJobRetained != mock.isJobRetained {
+				t.Fatalf("Test %q failed: expected the experiment job to be retained: %v, got %v", name, mock.isJobRetained, isJobRetained)
+			}
+			chaosEngine, err = client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("fail to get chaosengine after status patch for %v test, err: %v", name, err)
+			}
+			if chaosEngine.Status.Experiments[0].Status != ExperimentStatusInterrupted {
+				t.Fatalf("Test %q failed: expected experiment status is %v, got %v", name, ExperimentStatusInterrupted, chaosEngine.Status.Experiments[0].Status)
+			}
+		})
+	}
+}
+
+func TestHaltExperiment(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	experiment := ExperimentDetails{
+		Name:      "Fake-Exp-Name",
+		Namespace: "Fake NameSpace",
+		JobName:   "fake-job-name",
+	}
+
+	tests := map[string]struct {
+		cause    error
+		isHalted bool
+	}{
+		"Test Negative-1": {
+			cause:    nil,
+			isHalted: false,
+		},
+		"Test Negative-2": {
+			cause:    context.DeadlineExceeded,
+			isHalted: false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			if isHalted := engineDetails.HaltExperiment(mock.cause, &experiment, client); isHalted != mock.isHalted {
+				t.Fatalf("Test %q failed: expected the experiment to be halted: %v, got %v", name, mock.isHalted, isHalted)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// ResourceSnapshot is the resolved view of the chaosexperiment and the chaosengine, taken once per experiment.
+// Each resource is fetched on its first use only, and all the values of the experiment are derived from it,
+// so that they are consistent with each other even if the chaos resources are updated in the meantime
+type ResourceSnapshot struct {
+	experiment *v1alpha1.ChaosExperiment
+	engine     *v1alpha1.ChaosEngine
+	// ExperimentResourceVersion is the resourceVersion of the chaosexperiment inside the snapshot
+	ExperimentResourceVersion string
+	// EngineResourceVersion is the resourceVersion of the chaosengine inside the snapshot
+	EngineResourceVersion string
+}
+
+// chaosExperiment returns a copy of the chaosexperiment inside the snapshot, fetching it if not fetched yet
+// The copy keeps the snapshot immutable, as the setters are free to modify the returned object
+func (expDetails *ExperimentDetails) chaosExperiment(clients ClientSets) (*v1alpha1.ChaosExperiment, error) {
+	if expDetails.Snapshot.experiment == nil {
+		experiment, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(expDetails.Namespace).Get(context.Background(), expDetails.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Errorf("unable to get %v ChaosExperiment in namespace: %v, error: %v", expDetails.Name, expDetails.Namespace, err)
+		}
+		expDetails.Snapshot.experiment = experiment
+		expDetails.Snapshot.ExperimentResourceVersion = experiment.ResourceVersion
+		log.Infof("snapshot of ChaosExperiment: %v taken at resourceVersion: %v", expDetails.Name, experiment.ResourceVersion)
+	}
+	return expDetails.Snapshot.experiment.DeepCopy(), nil
+}
+
+// chaosEngine returns a copy of the chaosengine inside the snapshot, fetching it if not fetched yet
+func (expDetails *ExperimentDetails) chaosEngine(engineName string, clients ClientSets) (*v1alpha1.ChaosEngine, error) {
+	if expDetails.Snapshot.engine == nil {
+		engineDetails := EngineDetails{Name: engineName, EngineNamespace: expDetails.Namespace}
+		engine, err := engineDetails.GetChaosEngine(context.Background(), clients)
+		if err != nil {
+			return nil, err
+		}
+		expDetails.Snapshot.engine = engine
+		expDetails.Snapshot.EngineResourceVersion = engine.ResourceVersion
+		log.Infof("snapshot of ChaosEngine: %v for Chaos Experiment: %v taken at resourceVersion: %v", engineName, expDetails.Name, engine.ResourceVersion)
+	}
+	return expDetails.Snapshot.engine.DeepCopy(), nil
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	litmusFakeClientset "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned/fake"
+)
+
+func TestResourceSnapshot(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+		UID:             "Fake UID",
+	}
+	experiment := ExperimentDetails{
+		Name:      "Fake-Exp-Name",
+		Namespace: "Fake NameSpace",
+		JobName:   "fake-job-name",
+		envMap:    make(map[string]v1.EnvVar),
+	}
+
+	client := CreateFakeClient(t)
+	chaosExperiment := &v1alpha1.ChaosExperiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            experiment.Name,
+			Namespace:       experiment.Namespace,
+			ResourceVersion: "1",
+		},
+		Spec: v1alpha1.ChaosExperimentSpec{
+			Definition: v1alpha1.ExperimentDef{
+				Image:  "fake-exp-image",
+				Labels: map[string]string{"name": experiment.Name},
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(experiment.Namespace).Create(context.Background(), chaosExperiment, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("experiment not created, err: %v", err)
+	}
+	chaosEngine := &v1alpha1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            engineDetails.Name,
+			Namespace:       engineDetails.EngineNamespace,
+			ResourceVersion: "2",
+		},
+		Spec: v1alpha1.ChaosEngineSpec{
+			Experiments: []v1alpha1.ExperimentList{
+				{
+					Name: experiment.Name,
+				},
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("engine not created, err: %v", err)
+	}
+
+	if err := experiment.HandleChaosExperimentExistence(engineDetails, client); err != nil {
+		t.Fatalf("fail to check the existence of the experiment, err: %v", err)
+	}
+	// the chaosexperiment is updated once the snapshot is taken, it shouldn't be observed by the setters
+	chaosExperiment.Spec.Definition.Image = "updated-exp-image"
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosExperiments(experiment.Namespace).Update(context.Background(), chaosExperiment, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("experiment not updated, err: %v", err)
+	}
+	if err := experiment.SetValueFromChaosResources(&engineDetails, client); err != nil {
+		t.Fatalf("fail to set values from the chaos resources, err: %v", err)
+	}
+	if err := experiment.SetDefaultEnvFromChaosExperiment(client); err != nil {
+		t.Fatalf("fail to set the default envs, err: %v", err)
+	}
+	if err := experiment.SetOverrideEnvFromChaosEngine(engineDetails.Name, client); err != nil {
+		t.Fatalf("fail to override the envs, err: %v", err)
+	}
+	if err := experiment.SetSideCarDetails(engineDetails.Name, client); err != nil {
+		t.Fatalf("fail to set the sidecar details, err: %v", err)
+	}
+	if err := experiment.SetConfigMaps(client, engineDetails); err != nil {
+		t.Fatalf("fail to set the configmaps, err: %v", err)
+	}
+	if err := experiment.SetSecrets(client, engineDetails); err != nil {
+		t.Fatalf("fail to set the secrets, err: %v", err)
+	}
+	if err := experiment.SetHostFileVolumes(client, engineDetails); err != nil {
+		t.Fatalf("fail to set the hostFileVolumes, err: %v", err)
+	}
+
+	if experiment.ExpImage != "fake-exp-image" {
+		t.Fatalf("expected the image to be taken from the snapshot, got %v", experiment.ExpImage)
+	}
+	if experiment.Snapshot.ExperimentResourceVersion != "1" || experiment.Snapshot.EngineResourceVersion != "2" {
+		t.Fatalf("expected the snapshot to be taken at resourceVersions 1 and 2, got %v and %v", experiment.Snapshot.ExperimentResourceVersion, experiment.Snapshot.EngineResourceVersion)
+	}
+	if _, ok := experiment.Snapshot.experiment.Spec.Definition.Labels["chaosUID"]; ok {
+		t.Fatalf("expected the snapshot to stay unmodified by the setters")
+	}
+	gets := map[string]int{}
+	for _, action := range client.LitmusClient.(*litmusFakeClientset.Clientset).Actions() {
+		if action.GetVerb() == "get" {
+			gets[action.GetResource().Resource]++
+		}
+	}
+	if gets["chaosexperiments"] != 1 || gets["chaosengines"] != 1 {
+		t.Fatalf("expected the chaosexperiment and the chaosengine to be fetched once, got %v and %v", gets["chaosexperiments"], gets["chaosengines"])
+	}
+
+}
@@ -36,6 +36,16 @@ func (expStatus *ExperimentStatus) CompletedExperimentStatus(chaosResult *v1alph
 	expStatus.Verdict = string(chaosResult.Status.ExperimentStatus.Verdict)
 }
 
+// TerminationResultExperimentStatus fills up ExperimentStatus Structure with the verdict written inside the termination log of the chaos container
+func (expStatus *ExperimentStatus) TerminationResultExperimentStatus(result *TerminationResult, expName, engineName, experimentPodName string) {
+	expStatus.Name = expName
+	expStatus.Runner = engineName + "-runner"
+	expStatus.ExpPod = experimentPodName
+	expStatus.Status = v1alpha1.ExperimentStatusCompleted
+	expStatus.LastUpdateTime = metav1.Now()
+	expStatus.Verdict = result.Verdict
+}
+
 // NotFoundExperimentStatus initilize experiment struct using the following values.
 func (expStatus *ExperimentStatus) NotFoundExperimentStatus(expName, engineName string) {
 	expStatus.Name = expName
@@ -46,6 +56,77 @@ func (expStatus *ExperimentStatus) NotFoundExperimentStatus(expName, engineName
 	expStatus.LastUpdateTime = metav1.Now()
 }
 
+// UpstreamFailedExperimentStatus fills up ExperimentStatus Structure for an experiment skipped due to a failed upstream experiment
+func (expStatus *ExperimentStatus) UpstreamFailedExperimentStatus(expName, engineName string) {
+	expStatus.Name = expName
+	expStatus.Runner = engineName + "-runner"
+	expStatus.ExpPod = "N/A"
+	expStatus.Status = v1alpha1.ExperimentSkipped
+	expStatus.Verdict = "N/A"
+	expStatus.LastUpdateTime = metav1.Now()
+}
+
+// AbortedExperimentStatus fills up ExperimentStatus Structure for an experiment aborted as the chaosengine is stopped
+func (expStatus *ExperimentStatus) AbortedExperimentStatus(expName, engineName, experimentPodName string) {
+	expStatus.Name = expName
+	expStatus.Runner = engineName + "-runner"
+	expStatus.ExpPod = experimentPodName
+	expStatus.Status = v1alpha1.ExperimentStatusAborted
+	expStatus.Verdict = string(v1alpha1.ResultVerdictStopped)
+	expStatus.LastUpdateTime = metav1.Now()
+}
+
+// InterruptedExperimentStatus fills up ExperimentStatus Structure for an experiment halted as the chaos-runner is terminated
+func (expStatus *ExperimentStatus) InterruptedExperimentStatus(expName, engineName, experimentPodName string) {
+	expStatus.Name = expName
+	expStatus.Runner = engineName + "-runner"
+	expStatus.ExpPod = experimentPodName
+	expStatus.Status = ExperimentStatusInterrupted
+	expStatus.Verdict = string(v1alpha1.ResultVerdictStopped)
+	expStatus.LastUpdateTime = metav1.Now()
+}
+
+// NotRunExperimentStatus fills up ExperimentStatus Structure for an experiment which is not executed as the fail policy halted the run
+func (expStatus *ExperimentStatus) NotRunExperimentStatus(expName, engineName string) {
+	expStatus.Name = expName
+	expStatus.Runner = engineName + "-runner"
+	expStatus.ExpPod = "N/A"
+	expStatus.Status = ExperimentStatusNotRun
+	expStatus.Verdict = "N/A"
+	expStatus.LastUpdateTime = metav1.Now()
+}
+
+// TimedOutExperimentStatus fills up ExperimentStatus Structure for an experiment whose job exceeded its maximum duration
+func (expStatus *ExperimentStatus) TimedOutExperimentStatus(expName, engineName, experimentPodName string) {
+	expStatus.Name = expName
+	expStatus.Runner = engineName + "-runner"
+	expStatus.ExpPod = experimentPodName
+	expStatus.Status = ExperimentStatusTimedOut
+	expStatus.Verdict = string(v1alpha1.ResultVerdictError)
+	expStatus.LastUpdateTime = metav1.Now()
+}
+
+// VerdictTimedOutExperimentStatus fills up ExperimentStatus Structure for an experiment whose chaosresult verdict is still awaited after the verdict timeout
+func (expStatus *ExperimentStatus) VerdictTimedOutExperimentStatus(expName, engineName, experimentPodName string) {
+	expStatus.Name = expName
+	expStatus.Runner = engineName + "-runner"
+	expStatus.ExpPod = experimentPodName
+	expStatus.Status = ExperimentStatusVerdictTimedOut
+	expStatus.Verdict = string(v1alpha1.ResultVerdictError)
+	expStatus.LastUpdateTime = metav1.Now()
+}
+
+// PodFailedExperimentStatus fills up ExperimentStatus Structure for an experiment whose chaos pod failed,
+// the status is set to the classified reason of the failure, e.g. ImagePullBackOff
+func (expStatus *ExperimentStatus) PodFailedExperimentStatus(expName, engineName, experimentPodName, reason string) {
+	expStatus.Name = expName
+	expStatus.Runner = engineName + "-runner"
+	expStatus.ExpPod = experimentPodName
+	expStatus.Status = v1alpha1.ExperimentStatus(reason)
+	expStatus.Verdict = string(v1alpha1.ResultVerdictError)
+	expStatus.LastUpdateTime = metav1.Now()
+}
+
 // SkippedExperimentStatus fills up  ExperimentStatus Structure with skipped value
 func (expStatus *ExperimentStatus) SkippedExperimentStatus(expName, engineName string) {
 	expStatus.Name = expName
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+	"github.com/litmuschaos/chaos-runner/pkg/metrics"
+)
+
+// RunSummaryAnnotation is the chaosengine annotation holding the overall outcome of the run, e.g.
+// runner/summary: '{"total": 3, "passed": 2, "failed": 1, "startTime": "2021-06-01T10:00:00Z", "completionTime": "2021-06-01T10:05:00Z", "duration": "5m0s"}'
+const RunSummaryAnnotation = "runner/summary"
+
+// RunSummary contains the aggregated outcome of the experiments of the run
+type RunSummary struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+	// Errored contains the experiments which didn't reach a verdict, e.g. timed out or failed chaos pod
+	Errored  int `json:"errored,omitempty"`
+	Skipped  int `json:"skipped,omitempty"`
+	NotFound int `json:"notFound,omitempty"`
+	// Stopped contains the experiments halted as the chaosengine is stopped or the chaos-runner is interrupted
+	Stopped        int         `json:"stopped,omitempty"`
+	StartTime      metav1.Time `json:"startTime"`
+	CompletionTime metav1.Time `json:"completionTime"`
+	Duration       string      `json:"duration,omitempty"`
+}
+
+// IsPassed checks whether all the experiments of the run passed, a run without any experiment is not considered as passed
+func (summary RunSummary) IsPassed() bool {
+	return summary.Total > 0 && summary.Passed == summary.Total
+}
+
+// String returns a short description of the summary, as reported inside the events
+func (summary RunSummary) String() string {
+	description := []string{
+		"total: " + strconv.Itoa(summary.Total),
+		"passed: " + strconv.Itoa(summary.Passed),
+		"failed: " + strconv.Itoa(summary.Failed),
+	}
+	counts := []struct {
+		name  string
+		count int
+	}{
+		{"errored", summary.Errored},
+		{"skipped", summary.Skipped},
+		{"notFound", summary.NotFound},
+		{"stopped", summary.Stopped},
+	}
+	for _, c := range counts {
+		if c.count != 0 {
+			description = append(description, c.name+": "+strconv.Itoa(c.count))
+		}
+	}
+	if summary.Duration != "" {
+		description = append(description, "duration: "+summary.Duration)
+	}
+	return strings.Join(description, ", ")
+}
+
+// summarizeExperimentStatuses aggregates the status entries of the given experiments
+// The status takes precedence over the verdict, as the skipped experiments are marked with a Fail verdict
+func summarizeExperimentStatuses(statuses []v1alpha1.ExperimentStatuses, experiments []string) RunSummary {
+	var summary RunSummary
+	for _, expName := range experiments {
+		summary.Total++
+		index := checkStatusListForExp(statuses, expName)
+		if index == -1 {
+			summary.Skipped++
+			continue
+		}
+		switch status := statuses[index]; {
+		case status.Status == v1alpha1.ExperimentStatusNotFound:
+			summary.NotFound++
+		case status.Status == v1alpha1.ExperimentSkipped, status.Status == ExperimentStatusNotRun:
+			summary.Skipped++
+		case status.Verdict == string(v1alpha1.ResultVerdictPassed):
+			summary.Passed++
+		case status.Verdict == string(v1alpha1.ResultVerdictFailed):
+			summary.Failed++
+		case status.Verdict == string(v1alpha1.ResultVerdictStopped):
+			summary.Stopped++
+		default:
+			summary.Errored++
+		}
+	}
+	return summary
+}
+
+// observeExperimentVerdicts counts the experiments of the run by the verdict of their status entries
+func observeExperimentVerdicts(statuses []v1alpha1.ExperimentStatuses, experiments []string) {
+	for _, expName := range experiments {
+		verdict := "N/A"
+		if index := checkStatusListForExp(statuses, expName); index != -1 && statuses[index].Verdict != "" {
+			verdict = statuses[index].Verdict
+		}
+		metrics.ObserveExperimentVerdict(expName, verdict)
+	}
+}
+
+// summarizeRun computes the summary of the run from the given chaosengine
+func (engineDetails EngineDetails) summarizeRun(expEngine *v1alpha1.ChaosEngine) *RunSummary {
+	summary := summarizeExperimentStatuses(expEngine.Status.Experiments, engineDetails.Experiments)
+	summary.CompletionTime = metav1.Now()
+	if !engineDetails.StartTime.IsZero() {
+		summary.StartTime = metav1.NewTime(engineDetails.StartTime)
+		summary.Duration = summary.CompletionTime.Sub(engineDetails.StartTime).Round(time.Second).String()
+	}
+	return &summary
+}
+
+// RecordRunSummary writes the summary of the run inside the chaosengine annotations,
+// and generates the ChaosEngineCompleted event carrying it, till the context is done
+// The experiments are counted by their verdict along with the summary
+func (engineDetails EngineDetails) RecordRunSummary(ctx context.Context, clients ClientSets) {
+	expEngine, err := engineDetails.GetChaosEngine(ctx, clients)
+	if err != nil {
+		log.Errorf("unable to summarize the run, error: %v", err)
+		return
+	}
+	summary := engineDetails.summarizeRun(expEngine)
+	observeExperimentVerdicts(expEngine.Status.Experiments, engineDetails.Experiments)
+	log.Infof("Chaos Engine: %v is completed, %v", engineDetails.Name, summary.String())
+
+	encoded, err := json.Marshal(summary)
+	if err != nil {
+		log.Errorf("unable to marshal the run summary, error: %v", err)
+		return
+	}
+	if err := engineDetails.PatchChaosEngineAnnotations(ctx, map[string]string{RunSummaryAnnotation: string(encoded)}, clients); err != nil {
+		log.Errorf("unable to record the run summary, error: %v", err)
+	}
+	engineDetails.ChaosEngineCompleted(*summary, clients)
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+)
+
+func TestSummarizeExperimentStatuses(t *testing.T) {
+	statuses := []v1alpha1.ExperimentStatuses{
+		{Name: "exp-passed", Status: v1alpha1.ExperimentStatusCompleted, Verdict: "Pass"},
+		{Name: "exp-failed", Status: v1alpha1.ExperimentStatusCompleted, Verdict: "Fail"},
+		{Name: "exp-timed-out", Status: ExperimentStatusTimedOut, Verdict: "Error"},
+		{Name: "exp-skipped", Status: v1alpha1.ExperimentSkipped, Verdict: "Fail"},
+		{Name: "exp-not-run", Status: ExperimentStatusNotRun, Verdict: "N/A"},
+		{Name: "exp-not-found", Status: v1alpha1.ExperimentStatusNotFound, Verdict: "Fail"},
+		{Name: "exp-aborted", Status: v1alpha1.ExperimentStatusAborted, Verdict: "Stopped"},
+	}
+	experiments := []string{"exp-passed", "exp-failed", "exp-timed-out", "exp-skipped", "exp-not-run", "exp-not-found", "exp-aborted"}
+
+	summary := summarizeExperimentStatuses(statuses, experiments)
+	expected := RunSummary{Total: 7, Passed: 1, Failed: 1, Errored: 1, Skipped: 2, NotFound: 1, Stopped: 1}
+	if summary != expected {
+		t.Fatalf("expected the summary to be %+v, got %+v", expected, summary)
+	}
+}
+
+func TestRunSummaryIsPassed(t *testing.T) {
+	tests := map[string]struct {
+		summary  RunSummary
+		isPassed bool
+	}{
+		"Test Positive-1": {
+			summary:  RunSummary{Total: 2, Passed: 2},
+			isPassed: true,
+		},
+		"Test Negative-1": {
+			summary:  RunSummary{Total: 2, Passed: 1, Failed: 1},
+			isPassed: false,
+		},
+		"Test Negative-2": {
+			summary:  RunSummary{},
+			isPassed: false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			if mock.summary.IsPassed() != mock.isPassed {
+				t.Fatalf("Test %q failed: expected the run to be passed: %v, got %v", name, mock.isPassed, mock.summary.IsPassed())
+			}
+		})
+	}
+}
+
+func TestRecordRunSummary(t *testing.T) {
+	tests := map[string]struct {
+		verdicts          []string
+		expectedEventType string
+	}{
+		"Test Positive-1": {
+			verdicts:          []string{"Pass", "Pass"},
+			expectedEventType: "Normal",
+		},
+		"Test Positive-2": {
+			verdicts:          []string{"Pass", "Fail"},
+			expectedEventType: "Warning",
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{
+				Name:            "Fake Engine",
+				EngineNamespace: "Fake NameSpace",
+				UID:             "Fake UID",
+				Experiments:     []string{"exp-1", "exp-2"},
+				StartTime:       time.Now().Add(-90 * time.Second),
+			}
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+			}
+			for i, expName := range engineDetails.Experiments {
+				chaosEngine.Status.Experiments = append(chaosEngine.Status.Experiments, v1alpha1.ExperimentStatuses{
+					Name:    expName,
+					Status:  v1alpha1.ExperimentStatusCompleted,
+					Verdict: mock.verdicts[i],
+				})
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+
+			engineDetails.RecordRunSummary(context.Background(), client)
+
+			chaosEngine, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("fail to get chaosengine for %v test, err: %v", name, err)
+			}
+			var summary RunSummary
+			if err := json.Unmarshal([]byte(chaosEngine.Annotations[RunSummaryAnnotation]), &summary); err != nil {
+				t.Fatalf("Test %q failed: unable to parse the summary annotation, err: %v", name, err)
+			}
+			if summary.Total != 2 || summary.Duration != "1m30s" {
+				t.Fatalf("Test %q failed: expected 2 experiments in 1m30s, got %v in %v", name, summary.Total, summary.Duration)
+			}
+			client.EventRecorder.Flush(EventsFlushTimeout)
+			events, err := client.KubeClient.CoreV1().Events(engineDetails.EngineNamespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("fail to list the events for %v test, err: %v", name, err)
+			}
+			if len(events.Items) != 1 || events.Items[0].Reason != ChaosEngineCompletedReason || events.Items[0].Type != mock.expectedEventType {
+				t.Fatalf("Test %q failed: expected a %v %v event, got %v", name, mock.expectedEventType, ChaosEngineCompletedReason, events.Items)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// TerminationAnnotation is the per-experiment chaosengine annotation holding how the chaos container ended, e.g.
+// pod-delete/termination: '{"exitCode": 1, "reason": "Error", "message": "unable to get the target pods"}'
+// The experiment status of the chaosengine doesn't have any field to hold it, hence it is kept as an annotation
+const TerminationAnnotation = "termination"
+
+// ContainerTermination contains the details of the terminated chaos container
+type ContainerTermination struct {
+	ExitCode int32  `json:"exitCode"`
+	Reason   string `json:"reason,omitempty"`
+	// Message is the content of the termination log of the chaos container, i.e. /dev/termination-log
+	Message string `json:"message,omitempty"`
+}
+
+// String returns a short description of the termination, as logged by the runner
+func (termination *ContainerTermination) String() string {
+	description := "exit code: " + strconv.FormatInt(int64(termination.ExitCode), 10)
+	if termination.Reason != "" {
+		description += ", reason: " + termination.Reason
+	}
+	if termination.Message != "" {
+		description += ", message: " + termination.Message
+	}
+	return description
+}
+
+// chaosContainerTermination returns the terminated state of the chaos container, or nil if it isn't terminated yet
+func chaosContainerTermination(pod *corev1.Pod, jobName string) *ContainerTermination {
+	for _, container := range pod.Status.ContainerStatuses {
+		//NOTE: The name of container inside chaos-pod is same as the chaos job name
+		if !strings.Contains(container.Name, jobName) || container.State.Terminated == nil {
+			continue
+		}
+		return &ContainerTermination{
+			ExitCode: container.State.Terminated.ExitCode,
+			Reason:   container.State.Terminated.Reason,
+			Message:  strings.TrimSpace(container.State.Terminated.Message),
+		}
+	}
+	return nil
+}
+
+// RecordTermination records how the chaos container ended inside the chaosengine annotations
+// It is a no-op if the chaos container isn't observed as terminated
+func (engineDetails EngineDetails) RecordTermination(experiment *ExperimentDetails, clients ClientSets) {
+	if experiment.Termination == nil {
+		return
+	}
+	log.Infof("chaos container of Chaos Experiment: %v terminated with %v", experiment.Name, experiment.Termination.String())
+	termination, err := json.Marshal(experiment.Termination)
+	if err != nil {
+		log.Errorf("unable to marshal the termination of Chaos Experiment: %v, error: %v", experiment.Name, err)
+		return
+	}
+	annotations := map[string]string{
+		experimentAnnotation(experiment.Name, TerminationAnnotation): string(termination),
+	}
+	if err := engineDetails.PatchChaosEngineAnnotations(context.Background(), annotations, clients); err != nil {
+		log.Errorf("unable to record the termination of Chaos Experiment: %v, error: %v", experiment.Name, err)
+	}
+}
+
+// VerdictSourceAnnotation is the per-experiment chaosengine annotation set once the verdict
+// of the experiment is taken from the termination log of the chaos container instead of the chaosresult
+const VerdictSourceAnnotation = "verdict-source"
+
+// TerminationLogVerdictSource is the value of the verdict-source annotation, once the termination log is used
+const TerminationLogVerdictSource = "TerminationLog"
+
+// TerminationResult is the contract between the experiment and the runner for the termination log of the chaos container.
+// The experiment may write it as JSON inside /dev/termination-log, e.g.
+// {"verdict": "Fail", "failStep": "ChaosInject", "probeSuccessPercentage": "50", "probes": [{"name": "check-app", "type": "httpProbe", "status": "Failed"}]}
+// It is used as the verdict source once the chaosresult is missing or still awaited
+type TerminationResult struct {
+	Verdict                string         `json:"verdict"`
+	FailStep               string         `json:"failStep,omitempty"`
+	ProbeSuccessPercentage string         `json:"probeSuccessPercentage,omitempty"`
+	Probes                 []ProbeSummary `json:"probes,omitempty"`
+}
+
+// ProbeSummary is the outcome of a single probe of the experiment
+type ProbeSummary struct {
+	Name   string `json:"name"`
+	Type   string `json:"type,omitempty"`
+	Status string `json:"status"`
+}
+
+// ParseTerminationResult parses the termination log of the chaos container as a TerminationResult
+// It returns an error if the termination log doesn't follow the contract or doesn't hold a final verdict
+func ParseTerminationResult(message string) (*TerminationResult, error) {
+	var result TerminationResult
+	if err := json.Unmarshal([]byte(message), &result); err != nil {
+		return nil, errors.Errorf("unable to parse the termination log, error: %v", err)
+	}
+	switch v1alpha1.ResultVerdict(result.Verdict) {
+	case v1alpha1.ResultVerdictPassed, v1alpha1.ResultVerdictFailed, v1alpha1.ResultVerdictError, v1alpha1.ResultVerdictStopped:
+		return &result, nil
+	}
+	return nil, errors.Errorf("termination log doesn't hold a final verdict, got %q", result.Verdict)
+}
+
+// terminationResult returns the result written by the experiment inside the termination log of the chaos container,
+// or nil if the chaos container isn't terminated or the termination log doesn't follow the contract
+func (expDetails *ExperimentDetails) terminationResult() *TerminationResult {
+	if expDetails.Termination == nil || expDetails.Termination.Message == "" {
+		return nil
+	}
+	result, err := ParseTerminationResult(expDetails.Termination.Message)
+	if err != nil {
+		log.Warnf("[skip]: unable to use the termination log of Chaos Experiment: %v as verdict source, error: %v", expDetails.Name, err)
+		return nil
+	}
+	return result
+}
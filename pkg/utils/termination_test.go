@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestChaosContainerTermination(t *testing.T) {
+	jobName := "fake-job-name"
+	tests := map[string]struct {
+		containers  []v1.ContainerStatus
+		termination *ContainerTermination
+	}{
+		"Test Positive-1": {
+			containers: []v1.ContainerStatus{
+				{
+					Name:  jobName,
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: "unable to get the target pods\n"}},
+				},
+			},
+			termination: &ContainerTermination{ExitCode: 1, Reason: "Error", Message: "unable to get the target pods"},
+		},
+		"Test Positive-2": {
+			containers: []v1.ContainerStatus{
+				{
+					Name:  "fake-sidecar",
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 2, Reason: "Error"}},
+				},
+				{
+					Name:  jobName,
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}},
+				},
+			},
+			termination: &ContainerTermination{ExitCode: 0, Reason: "Completed"},
+		},
+		"Test Negative-1": {
+			containers: []v1.ContainerStatus{
+				{
+					Name:  jobName,
+					State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				},
+			},
+			termination: nil,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := &v1.Pod{Status: v1.PodStatus{ContainerStatuses: mock.containers}}
+			termination := chaosContainerTermination(pod, jobName)
+			if (termination == nil) != (mock.termination == nil) {
+				t.Fatalf("Test %q failed: expected termination %v, got %v", name, mock.termination, termination)
+			}
+			if termination != nil && *termination != *mock.termination {
+				t.Fatalf("Test %q failed: expected termination %v, got %v", name, *mock.termination, *termination)
+			}
+		})
+	}
+}
+
+func TestRecordTermination(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	experiment := ExperimentDetails{
+		Name:        "Fake-Exp-Name",
+		Namespace:   "Fake NameSpace",
+		JobName:     "fake-job-name",
+		Termination: &ContainerTermination{ExitCode: 1, Reason: "Error", Message: "unable to get the target pods"},
+	}
+
+	client := CreateFakeClient(t)
+	chaosEngine := &v1alpha1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.Name,
+			Namespace: engineDetails.EngineNamespace,
+		},
+		Status: v1alpha1.ChaosEngineStatus{
+			Experiments: []v1alpha1.ExperimentStatuses{
+				{
+					Name:   experiment.Name,
+					Status: v1alpha1.ExperimentStatusRunning,
+				},
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("engine not created, err: %v", err)
+	}
+
+	// the chaosresult is missing, the termination is recorded nevertheless and reported inside the error
+	err := engineDetails.UpdateEngineWithResult(context.Background(), &experiment, client)
+	if err == nil || !strings.Contains(err.Error(), "exit code: 1") {
+		t.Fatalf("expected the error to report the termination of the chaos container, got %v", err)
+	}
+
+	chaosEngine, err = client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("fail to get chaosengine, err: %v", err)
+	}
+	var termination ContainerTermination
+	if err := json.Unmarshal([]byte(chaosEngine.Annotations[experimentAnnotation(experiment.Name, TerminationAnnotation)]), &termination); err != nil {
+		t.Fatalf("fail to parse the termination annotation, err: %v", err)
+	}
+	if termination != *experiment.Termination {
+		t.Fatalf("expected the recorded termination to be %v, got %v", *experiment.Termination, termination)
+	}
+}
+
+func TestParseTerminationResult(t *testing.T) {
+	tests := map[string]struct {
+		message string
+		verdict string
+		isErr   bool
+	}{
+		"Test Positive-1": {
+			message: `{"verdict": "Fail", "failStep": "ChaosInject", "probeSuccessPercentage": "50", "probes": [{"name": "check-app", "type": "httpProbe", "status": "Failed"}]}`,
+			verdict: "Fail",
+			isErr:   false,
+		},
+		"Test Positive-2": {
+			message: `{"verdict": "Pass"}`,
+			verdict: "Pass",
+			isErr:   false,
+		},
+		"Test Negative-1": {
+			message: "unable to get the target pods",
+			isErr:   true,
+		},
+		"Test Negative-2": {
+			message: `{"verdict": "Awaited"}`,
+			isErr:   true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseTerminationResult(mock.message)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			if err == nil && result.Verdict != mock.verdict {
+				t.Fatalf("Test %q failed: expected verdict is %v, got %v", name, mock.verdict, result.Verdict)
+			}
+		})
+	}
+}
+
+func TestUpdateEngineWithTerminationResult(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+
+	tests := map[string]struct {
+		chaosResultVerdict v1alpha1.ResultVerdict
+		isChaosResult      bool
+		message            string
+		expectedVerdict    string
+		isErr              bool
+	}{
+		"Test Positive-1": {
+			isChaosResult:   false,
+			message:         `{"verdict": "Fail", "failStep": "ChaosInject"}`,
+			expectedVerdict: "Fail",
+		},
+		"Test Positive-2": {
+			isChaosResult:      true,
+			chaosResultVerdict: v1alpha1.ResultVerdictAwaited,
+			message:            `{"verdict": "Pass"}`,
+			expectedVerdict:    "Pass",
+		},
+		"Test Positive-3": {
+			isChaosResult:      true,
+			chaosResultVerdict: v1alpha1.ResultVerdictFailed,
+			message:            `{"verdict": "Pass"}`,
+			expectedVerdict:    "Fail",
+		},
+		"Test Negative-1": {
+			isChaosResult: false,
+			message:       "unable to get the target pods",
+			isErr:         true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			experiment := ExperimentDetails{
+				Name:               "Fake-Exp-Name",
+				Namespace:          "Fake NameSpace",
+				JobName:            "fake-job-name",
+				StatusCheckTimeout: 2,
+				Termination:        &ContainerTermination{ExitCode: 1, Reason: "Error", Message: mock.message},
+			}
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Status: v1alpha1.ChaosEngineStatus{
+					Experiments: []v1alpha1.ExperimentStatuses{
+						{
+							Name:   experiment.Name,
+							Status: v1alpha1.ExperimentStatusRunning,
+						},
+					},
+				},
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			chaosPod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   experiment.JobName + "-abcde",
+					Labels: map[string]string{"job-name": experiment.JobName},
+				},
+			}
+			if _, err := client.KubeClient.CoreV1().Pods(experiment.Namespace).Create(context.Background(), chaosPod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("fail to create chaos pod for %v test, err: %v", name, err)
+			}
+			if mock.isChaosResult {
+				chaosResult := &v1alpha1.ChaosResult{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      engineDetails.Name + "-" + experiment.Name,
+						Namespace: engineDetails.EngineNamespace,
+					},
+					Spec: v1alpha1.ChaosResultSpec{
+						EngineName:     engineDetails.Name,
+						ExperimentName: experiment.Name,
+					},
+					Status: v1alpha1.ChaosResultStatus{
+						ExperimentStatus: v1alpha1.TestStatus{
+							Verdict: mock.chaosResultVerdict,
+						},
+					},
+				}
+				if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosResults(engineDetails.EngineNamespace).Create(context.Background(), chaosResult, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("chaosresult not created for %v test, err: %v", name, err)
+				}
+			}
+
+			err := engineDetails.UpdateEngineWithResult(context.Background(), &experiment, client)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			if mock.isErr {
+				return
+			}
+			chaosEngine, err = client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("fail to get chaosengine for %v test, err: %v", name, err)
+			}
+			if chaosEngine.Status.Experiments[0].Verdict != mock.expectedVerdict || experiment.Verdict != mock.expectedVerdict {
+				t.Fatalf("Test %q failed: expected verdict is %v, got %v", name, mock.expectedVerdict, chaosEngine.Status.Experiments[0].Verdict)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+	"github.com/litmuschaos/chaos-runner/pkg/metrics"
+)
+
+// TimingsAnnotation is the per-experiment chaosengine annotation holding when the experiment ran, e.g.
+// pod-delete/timings: '{"launchTime": "2021-06-01T10:00:00Z", "podStartTime": "2021-06-01T10:00:05Z", "completionTime": "2021-06-01T10:01:35Z", "duration": "1m35s"}'
+// The experiment status of the chaosengine only holds the lastUpdateTime, hence it is kept as an annotation
+const TimingsAnnotation = "timings"
+
+// ExperimentTimings contains the timestamps of the lifecycle of the experiment
+type ExperimentTimings struct {
+	// LaunchTime is the time the first job of the experiment is launched at
+	LaunchTime *metav1.Time `json:"launchTime,omitempty"`
+	// PodStartTime is the time the last chaos pod of the experiment is started at
+	PodStartTime *metav1.Time `json:"podStartTime,omitempty"`
+	// CompletionTime is the time the experiment is completed at
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Duration is the total duration of the experiment, from its launch till its completion
+	Duration string `json:"duration,omitempty"`
+}
+
+// String returns a short description of the timings, as reported inside the events
+func (timings ExperimentTimings) String() string {
+	var description []string
+	if timings.LaunchTime != nil {
+		description = append(description, "launched at: "+timings.LaunchTime.UTC().Format(time.RFC3339))
+	}
+	if timings.PodStartTime != nil {
+		description = append(description, "chaos pod started at: "+timings.PodStartTime.UTC().Format(time.RFC3339))
+	}
+	if timings.CompletionTime != nil {
+		description = append(description, "completed at: "+timings.CompletionTime.UTC().Format(time.RFC3339))
+	}
+	if timings.Duration != "" {
+		description = append(description, "duration: "+timings.Duration)
+	}
+	return strings.Join(description, ", ")
+}
+
+// Attributes returns the timings as span attributes
+func (timings ExperimentTimings) Attributes() []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+	if timings.LaunchTime != nil {
+		attributes = append(attributes, attribute.String("experiment.launch_time", timings.LaunchTime.UTC().Format(time.RFC3339)))
+	}
+	if timings.PodStartTime != nil {
+		attributes = append(attributes, attribute.String("experiment.pod_start_time", timings.PodStartTime.UTC().Format(time.RFC3339)))
+	}
+	if timings.CompletionTime != nil {
+		attributes = append(attributes, attribute.String("experiment.completion_time", timings.CompletionTime.UTC().Format(time.RFC3339)))
+	}
+	if timings.LaunchTime != nil && timings.CompletionTime != nil {
+		attributes = append(attributes, attribute.Float64("experiment.duration_seconds", timings.CompletionTime.Sub(timings.LaunchTime.Time).Seconds()))
+	}
+	return attributes
+}
+
+// observeLaunch records the launch time of the experiment, once its first job is launched
+func (timings *ExperimentTimings) observeLaunch(launchTime time.Time) {
+	if timings.LaunchTime == nil && !launchTime.IsZero() {
+		timings.LaunchTime = &metav1.Time{Time: launchTime}
+	}
+}
+
+// observeJob records the launch time from the creation of the job, if it is launched by a previous chaos-runner pod
+func (timings *ExperimentTimings) observeJob(job *batchv1.Job) {
+	if job != nil {
+		timings.observeLaunch(job.CreationTimestamp.Time)
+	}
+}
+
+// observeChaosPod records the start time of the chaos pod, which is the newest one if the job controller replaced it
+func (timings *ExperimentTimings) observeChaosPod(pod *corev1.Pod) {
+	if pod.Status.StartTime != nil {
+		timings.PodStartTime = pod.Status.StartTime.DeepCopy()
+	}
+}
+
+// observeCompletion records the completion time of the experiment, taken from the job if it is completed
+func (timings *ExperimentTimings) observeCompletion(job *batchv1.Job) {
+	completionTime := metav1.Now()
+	if job != nil && job.Status.CompletionTime != nil {
+		completionTime = *job.Status.CompletionTime
+	}
+	timings.CompletionTime = &completionTime
+}
+
+// RecordTimings records the timings of the experiment inside the chaosengine annotations
+// The experiment is considered as completed at the time of the recording, if its completion isn't observed
+func (engineDetails EngineDetails) RecordTimings(experiment *ExperimentDetails, clients ClientSets) {
+	if experiment.Timings.LaunchTime == nil {
+		return
+	}
+	if experiment.Timings.CompletionTime == nil {
+		experiment.Timings.observeCompletion(nil)
+	}
+	duration := experiment.Timings.CompletionTime.Sub(experiment.Timings.LaunchTime.Time)
+	experiment.Timings.Duration = duration.Round(time.Second).String()
+	metrics.ObserveExperimentDuration(experiment.Name, duration)
+	log.Infof("Chaos Experiment: %v is %v", experiment.Name, experiment.Timings.String())
+
+	timings, err := json.Marshal(experiment.Timings)
+	if err != nil {
+		log.Errorf("unable to marshal the timings of Chaos Experiment: %v, error: %v", experiment.Name, err)
+		return
+	}
+	annotations := map[string]string{
+		experimentAnnotation(experiment.Name, TimingsAnnotation): string(timings),
+	}
+	if err := engineDetails.PatchChaosEngineAnnotations(context.Background(), annotations, clients); err != nil {
+		log.Errorf("unable to record the timings of Chaos Experiment: %v, error: %v", experiment.Name, err)
+	}
+}
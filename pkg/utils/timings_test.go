@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecordTimings(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	launchTime := metav1.NewTime(time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC))
+	podStartTime := metav1.NewTime(launchTime.Add(5 * time.Second))
+	completionTime := metav1.NewTime(launchTime.Add(95 * time.Second))
+
+	tests := map[string]struct {
+		timings          ExperimentTimings
+		job              *batchv1.Job
+		expectedDuration string
+		isRecorded       bool
+	}{
+		"Test Positive-1": {
+			timings: ExperimentTimings{LaunchTime: &launchTime, PodStartTime: &podStartTime},
+			job: &batchv1.Job{
+				Status: batchv1.JobStatus{CompletionTime: &completionTime},
+			},
+			expectedDuration: "1m35s",
+			isRecorded:       true,
+		},
+		"Test Positive-2": {
+			timings:          ExperimentTimings{LaunchTime: &launchTime, PodStartTime: &podStartTime, CompletionTime: &completionTime},
+			expectedDuration: "1m35s",
+			isRecorded:       true,
+		},
+		"Test Negative-1": {
+			timings:    ExperimentTimings{},
+			isRecorded: false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			experiment := ExperimentDetails{
+				Name:      "Fake-Exp-Name",
+				Namespace: "Fake NameSpace",
+				JobName:   "fake-job-name",
+				Timings:   mock.timings,
+			}
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			if mock.job != nil {
+				experiment.Timings.observeCompletion(mock.job)
+			}
+
+			engineDetails.RecordTimings(&experiment, client)
+
+			chaosEngine, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("fail to get chaosengine for %v test, err: %v", name, err)
+			}
+			annotation, ok := chaosEngine.Annotations[experimentAnnotation(experiment.Name, TimingsAnnotation)]
+			if ok != mock.isRecorded {
+				t.Fatalf("Test %q failed: expected the timings to be recorded: %v, got %v", name, mock.isRecorded, ok)
+			}
+			if !ok {
+				return
+			}
+			var timings ExperimentTimings
+			if err := json.Unmarshal([]byte(annotation), &timings); err != nil {
+				t.Fatalf("Test %q failed: unable to parse the timings annotation, err: %v", name, err)
+			}
+			if timings.Duration != mock.expectedDuration || !timings.CompletionTime.Equal(&completionTime) || !timings.PodStartTime.Equal(&podStartTime) {
+				t.Fatalf("Test %q failed: expected the duration to be %v, got %v", name, mock.expectedDuration, timings.Duration)
+			}
+			if description := experiment.Timings.String(); !strings.Contains(description, "duration: "+mock.expectedDuration) {
+				t.Fatalf("Test %q failed: expected the description to report the duration, got %v", name, description)
+			}
+			if attributes := experiment.Timings.Attributes(); len(attributes) != 4 {
+				t.Fatalf("Test %q failed: expected 4 span attributes, got %v", name, len(attributes))
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"flag"
+	"time"
 
 	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	clientV1alpha1 "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned"
@@ -11,6 +12,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/litmuschaos/chaos-runner/pkg/metrics"
 	"github.com/litmuschaos/chaos-runner/pkg/utils/k8s"
 	"github.com/litmuschaos/chaos-runner/pkg/utils/litmus"
 )
@@ -25,6 +27,30 @@ type EngineDetails struct {
 	AuxiliaryAppInfo string
 	UID              string
 	EngineNamespace  string
+	// Parallelism is the maximum number of experiments executed at once
+	Parallelism int
+	// Dependencies contains the upstream experiments of each experiment
+	Dependencies map[string][]string
+	// RetryPolicies contains the retry policy of each experiment
+	RetryPolicies map[string]RetryPolicy
+	// FailPolicy decides whether the run continues after an experiment didn't pass
+	FailPolicy FailPolicy
+	// MaxDurations contains the maximum duration in seconds of each experiment, if provided explicitly
+	MaxDurations map[string]int
+	// DeadlineBuffer is the number of seconds added to the TOTAL_CHAOS_DURATION to derive the maximum duration
+	DeadlineBuffer int
+	// VerdictTimeout is the number of seconds to wait for the chaosresult verdict to leave Awaited
+	VerdictTimeout int
+	// Render defines the dry-run mode, which renders the experiment jobs instead of launching them
+	Render RenderOptions
+	// StartTime is the time the chaos-runner started the run at
+	StartTime time.Time
+	// Notifiers are notified at each lifecycle point of the run
+	Notifiers []Notifier
+	// notifications delivers the notifications to the Notifiers, once started
+	notifications *notificationQueue
+	// StatusSubresource decides whether the chaosengine status is patched through the status subresource
+	StatusSubresource bool
 }
 
 // ExperimentDetails is for collecting all the experiment-related details
@@ -57,6 +83,37 @@ type ExperimentDetails struct {
 	TerminationGracePeriodSeconds int64
 	DefaultHealthCheck            string
 	SideCars                      []SideCar
+	// DependsOn contains the experiments which should pass before this experiment is started
+	DependsOn []string
+	// Verdict is the verdict of the experiment, derived from the chaosresult
+	Verdict string
+	// FailStep is the step the experiment failed at, derived from the chaosresult along with the verdict
+	FailStep string
+	// ProbeSuccessPercentage is the score of the probes of the experiment, derived from the chaosresult along with the verdict
+	ProbeSuccessPercentage string
+	// ChaosPodName is the name of the chaos pod launched by the experiment job
+	ChaosPodName string
+	// RetryPolicy defines how the experiment is retried after a transient failure
+	RetryPolicy RetryPolicy
+	// Attempt is the current attempt of the experiment, starting from 1
+	Attempt int
+	// MaxDuration is the maximum duration in seconds of the experiment provided in the chaosengine
+	MaxDuration int
+	// ActiveDeadlineSeconds is the activeDeadlineSeconds of the experiment job, unbounded if zero
+	ActiveDeadlineSeconds int64
+	// Finished marks an experiment already finished by a previous chaos-runner pod of the same run
+	Finished bool
+	// Adopted marks an experiment whose job, launched by a previous chaos-runner pod, is watched instead of being launched again
+	Adopted bool
+	// Termination contains how the chaos container ended, once it is terminated
+	Termination *ContainerTermination
+	// Snapshot is the view of the chaosexperiment and the chaosengine from which the experiment is derived
+	Snapshot ResourceSnapshot
+	// Timings contains when the experiment is launched, started and completed
+	Timings ExperimentTimings
+	// jobReference and podReference refer to the experiment job and chaos pod of the current attempt, which the events are attached to
+	jobReference *v1.ObjectReference
+	podReference *v1.ObjectReference
 }
 
 type SideCar struct {
@@ -77,6 +134,8 @@ type VolumeOpts struct {
 type ClientSets struct {
 	KubeClient   kubernetes.Interface
 	LitmusClient clientV1alpha1.Interface
+	// EventRecorder emits the events of the chaos-runner, created along with the KubeClient
+	EventRecorder *EventRecorder
 }
 
 // EventAttributes is for collecting all the events-related details
@@ -84,7 +143,8 @@ type EventAttributes struct {
 	Reason  string
 	Message string
 	Type    string
-	Name    string
+	// Related contains the objects the event is attached to along with the chaosengine, e.g. the experiment job and chaos pod
+	Related []*v1.ObjectReference
 }
 
 var (
@@ -92,6 +152,17 @@ var (
 	DefaultExpImagePullPolicy v1.PullPolicy = "Always"
 )
 
+const (
+	// ExperimentStatusInterrupted is status of Experiment which is halted as the chaos-runner is terminated
+	ExperimentStatusInterrupted v1alpha1.ExperimentStatus = "Interrupted"
+	// ExperimentStatusNotRun is status of Experiment which is not executed as the fail policy halted the run
+	ExperimentStatusNotRun v1alpha1.ExperimentStatus = "NotRun"
+	// ExperimentStatusTimedOut is status of Experiment whose job exceeded its maximum duration
+	ExperimentStatusTimedOut v1alpha1.ExperimentStatus = "TimedOut"
+	// ExperimentStatusVerdictTimedOut is status of Experiment whose chaosresult verdict is still awaited after the verdict timeout
+	ExperimentStatusVerdictTimedOut v1alpha1.ExperimentStatus = "VerdictTimedOut"
+)
+
 const (
 	// ExperimentDependencyCheckReason contains the reason for the dependency check event
 	ExperimentDependencyCheckReason string = "ExperimentDependencyCheck"
@@ -113,6 +184,28 @@ const (
 	ChaosResourceNotFoundReason string = "ChaosResourceNotFound"
 	// ExperimentSideCarPatchErrorReason contains the reason for the side-car-patch-error event
 	ExperimentSideCarPatchErrorReason string = "SideCarPatchError"
+	// ExperimentUpstreamFailedReason contains the reason for the upstream-experiment-failed event
+	ExperimentUpstreamFailedReason string = "UpstreamExperimentFailed"
+	// ExperimentAbortedReason contains the reason for the experiment-aborted event
+	ExperimentAbortedReason string = "ExperimentAborted"
+	// ChaosRunnerInterruptedReason contains the reason for the runner-interrupted event
+	ChaosRunnerInterruptedReason string = "ChaosRunnerInterrupted"
+	// ExperimentRetriedReason contains the reason for the experiment-retried event
+	ExperimentRetriedReason string = "ExperimentRetried"
+	// ExperimentNotRunReason contains the reason for the experiment-not-run event
+	ExperimentNotRunReason string = "ExperimentNotRun"
+	// ExperimentTimeoutReason contains the reason for the experiment-timeout event
+	ExperimentTimeoutReason string = "ExperimentTimeout"
+	// ExperimentPodFailedReason contains the reason for the experiment-pod-failed event
+	ExperimentPodFailedReason string = "ExperimentPodFailed"
+	// ExperimentVerdictTimeoutReason contains the reason for the experiment-verdict-timeout event
+	ExperimentVerdictTimeoutReason string = "ExperimentVerdictTimeout"
+	// ExperimentPodRestartedReason contains the reason for the experiment-pod-restarted event
+	ExperimentPodRestartedReason string = "ExperimentPodRestarted"
+	// ChaosEngineCompletedReason contains the reason for the chaosengine-completed event
+	ChaosEngineCompletedReason string = "ChaosEngineCompleted"
+	// ExperimentCompletedReason contains the reason for the experiment-completed event
+	ExperimentCompletedReason string = "ExperimentCompleted"
 )
 
 // GenerateClientSetFromKubeConfig will generation both ClientSets (k8s, and Litmus)
@@ -121,6 +214,8 @@ func (clientSets *ClientSets) GenerateClientSetFromKubeConfig() error {
 	if err != nil {
 		return err
 	}
+	// record the latency and the errors of the kubernetes API requests
+	config.Wrap(metrics.InstrumentRoundTripper)
 	k8sClientSet, err := k8s.GenerateK8sClientSet(config)
 	if err != nil {
 		return err
@@ -131,6 +226,7 @@ func (clientSets *ClientSets) GenerateClientSetFromKubeConfig() error {
 	}
 	clientSets.KubeClient = k8sClientSet
 	clientSets.LitmusClient = litmusClientSet
+	clientSets.EventRecorder = NewEventRecorder(k8sClientSet)
 
 	return nil
 }
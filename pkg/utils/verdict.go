@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+
+	litmuschaosv1alpha1 "github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+const (
+	// VerdictTimeoutAnnotation is the chaosengine annotation holding the seconds to wait for the chaosresult verdict
+	// to leave Awaited, once the chaos container is completed
+	VerdictTimeoutAnnotation = "runner/verdict-timeout"
+	// DefaultVerdictTimeout leaves room for the last update of the chaosresult by the experiment
+	DefaultVerdictTimeout = 60
+)
+
+// SetVerdictTimeoutFromEngine overrides the verdict timeout with the one provided in the chaosengine annotations
+func (engineDetails *EngineDetails) SetVerdictTimeoutFromEngine(engine *litmuschaosv1alpha1.ChaosEngine) *EngineDetails {
+	engineDetails.VerdictTimeout = DefaultVerdictTimeout
+	if value, ok := engine.Annotations[VerdictTimeoutAnnotation]; ok {
+		timeout, err := strconv.Atoi(value)
+		if err != nil || timeout < 0 {
+			log.Warnf("[skip]: invalid %v annotation value: %v", VerdictTimeoutAnnotation, value)
+		} else {
+			engineDetails.VerdictTimeout = timeout
+		}
+	}
+	return engineDetails
+}
+
+// isVerdictAwaited checks whether the experiment didn't update the chaosresult with its final verdict yet
+func isVerdictAwaited(chaosResult *litmuschaosv1alpha1.ChaosResult) bool {
+	return chaosResult.Status.ExperimentStatus.Verdict == litmuschaosv1alpha1.ResultVerdictAwaited
+}
+
+// WaitForFinalVerdict watches the chaosresult till its verdict leaves Awaited, or the verdict timeout is reached,
+// or the run context is done, e.g. once the chaosengine is stopped or the runner is interrupted
+// It returns the latest observed chaosresult, which is still awaited once the timeout is reached
+func (expDetails *ExperimentDetails) WaitForFinalVerdict(ctx context.Context, chaosResult *litmuschaosv1alpha1.ChaosResult, engineDetails EngineDetails, clients ClientSets) *litmuschaosv1alpha1.ChaosResult {
+	if !isVerdictAwaited(chaosResult) || engineDetails.VerdictTimeout <= 0 {
+		return chaosResult
+	}
+	log.WithContext(ctx).Infof("waiting for the final verdict of Chaos Experiment: %v, timeout: %vs", expDetails.Name, engineDetails.VerdictTimeout)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(engineDetails.VerdictTimeout)*time.Second)
+	defer cancel()
+	chaosResults := clients.LitmusClient.LitmuschaosV1alpha1().ChaosResults(chaosResult.Namespace)
+	resultWatch := objectWatch{
+		kind:      "ChaosResult",
+		name:      chaosResult.Name,
+		namespace: chaosResult.Namespace,
+		objType:   &litmuschaosv1alpha1.ChaosResult{},
+		list: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			return chaosResults.List(ctx, options)
+		},
+		watch: func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+			return chaosResults.Watch(ctx, options)
+		},
+		get: func(ctx context.Context) (runtime.Object, error) {
+			return chaosResults.Get(ctx, chaosResult.Name, metav1.GetOptions{})
+		},
+	}
+	waitUntil(ctx, resultWatch, func(obj runtime.Object) bool {
+		result, isResult := obj.(*litmuschaosv1alpha1.ChaosResult)
+		if !isResult {
+			return false
+		}
+		chaosResult = result
+		return !isVerdictAwaited(chaosResult)
+	})
+	return chaosResult
+}
+
+// VerdictTimeoutExperiment marks the experiment as timed out while waiting for the final verdict of its chaosresult
+func (engineDetails EngineDetails) VerdictTimeoutExperiment(experiment *ExperimentDetails, experimentPodName string, clients ClientSets) error {
+	log.Errorf("the verdict of Chaos Experiment: %v is still %v after %vs", experiment.Name, litmuschaosv1alpha1.ResultVerdictAwaited, engineDetails.VerdictTimeout)
+	experiment.Verdict = string(litmuschaosv1alpha1.ResultVerdictError)
+	experiment.ExperimentVerdictTimeout(engineDetails, clients)
+
+	var expStatus ExperimentStatus
+	expStatus.VerdictTimedOutExperimentStatus(experiment.Name, engineDetails.Name, experimentPodName)
+	return expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, clients)
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	litmusFakeClientset "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetVerdictTimeoutFromEngine(t *testing.T) {
+	tests := map[string]struct {
+		annotations     map[string]string
+		expectedTimeout int
+	}{
+		"Test Positive-1": {
+			annotations:     map[string]string{VerdictTimeoutAnnotation: "120"},
+			expectedTimeout: 120,
+		},
+		"Test Positive-2": {
+			annotations:     map[string]string{},
+			expectedTimeout: DefaultVerdictTimeout,
+		},
+		"Test Negative-1": {
+			annotations:     map[string]string{VerdictTimeoutAnnotation: "-1"},
+			expectedTimeout: DefaultVerdictTimeout,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			engineDetails := EngineDetails{}
+			engine := &v1alpha1.ChaosEngine{ObjectMeta: metav1.ObjectMeta{Annotations: mock.annotations}}
+			if engineDetails.SetVerdictTimeoutFromEngine(engine); engineDetails.VerdictTimeout != mock.expectedTimeout {
+				t.Fatalf("Test %q failed: expected verdict timeout is %v, got %v", name, mock.expectedTimeout, engineDetails.VerdictTimeout)
+			}
+		})
+	}
+}
+
+func TestUpdateEngineWithAwaitedResult(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+		VerdictTimeout:  1,
+	}
+
+	tests := map[string]struct {
+		finalVerdict    v1alpha1.ResultVerdict
+		expectedStatus  v1alpha1.ExperimentStatus
+		expectedVerdict string
+	}{
+		"Test Positive-1": {
+			finalVerdict:    v1alpha1.ResultVerdictPassed,
+			expectedStatus:  v1alpha1.ExperimentStatusCompleted,
+			expectedVerdict: string(v1alpha1.ResultVerdictPassed),
+		},
+		"Test Negative-1": {
+			expectedStatus:  ExperimentStatusVerdictTimedOut,
+			expectedVerdict: string(v1alpha1.ResultVerdictError),
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			experiment := ExperimentDetails{
+				Name:               "Fake-Exp-Name",
+				Namespace:          "Fake NameSpace",
+				JobName:            "fake-job-name",
+				StatusCheckTimeout: 2,
+			}
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Status: v1alpha1.ChaosEngineStatus{
+					Experiments: []v1alpha1.ExperimentStatuses{
+						{
+							Name:   experiment.Name,
+							Status: v1alpha1.ExperimentStatusRunning,
+						},
+					},
+				},
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			chaosPod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   experiment.JobName + "-abcde",
+					Labels: map[string]string{"job-name": experiment.JobName},
+				},
+			}
+			if _, err := client.KubeClient.CoreV1().Pods(experiment.Namespace).Create(context.Background(), chaosPod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("fail to create chaos pod for %v test, err: %v", name, err)
+			}
+			chaosResult := &v1alpha1.ChaosResult{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name + "-" + experiment.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Spec: v1alpha1.ChaosResultSpec{
+					EngineName:     engineDetails.Name,
+					ExperimentName: experiment.Name,
+				},
+				Status: v1alpha1.ChaosResultStatus{
+					ExperimentStatus: v1alpha1.TestStatus{
+						Verdict: v1alpha1.ResultVerdictAwaited,
+					},
+				},
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosResults(engineDetails.EngineNamespace).Create(context.Background(), chaosResult, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("chaosresult not created for %v test, err: %v", name, err)
+			}
+			if mock.finalVerdict != "" {
+				// the verdict is updated once the chaosresult is watched, as the fake watches don't replay the earlier changes
+				watched := notifyOnAction(&client.LitmusClient.(*litmusFakeClientset.Clientset).Fake, "watch", "chaosresults")
+				go func() {
+					<-watched
+					chaosResult.Status.ExperimentStatus.Verdict = mock.finalVerdict
+					if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosResults(engineDetails.EngineNamespace).Update(context.Background(), chaosResult, metav1.UpdateOptions{}); err != nil {
+						t.Errorf("chaosresult not updated for %v test, err: %v", name, err)
+					}
+				}()
+			}
+
+			if err := engineDetails.UpdateEngineWithResult(context.Background(), &experiment, client); err != nil {
+				t.Fatalf("Test %q failed: fail to update chaos engine with result, err: %v", name, err)
+			}
+			chaosEngine, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("fail to get chaosengine for %v test, err: %v", name, err)
+			}
+			expStatus := chaosEngine.Status.Experiments[0]
+			if expStatus.Status != mock.expectedStatus || expStatus.Verdict != mock.expectedVerdict {
+				t.Fatalf("Test %q failed: expected status %v with verdict %v, got %v with verdict %v", name, mock.expectedStatus, mock.expectedVerdict, expStatus.Status, expStatus.Verdict)
+			}
+		})
+	}
+}
+
+func TestWaitForFinalVerdictWithDoneContext(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+		VerdictTimeout:  DefaultVerdictTimeout,
+	}
+	experiment := ExperimentDetails{Name: "Fake-Exp-Name"}
+	client := CreateFakeClient(t)
+	chaosResult := &v1alpha1.ChaosResult{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.Name + "-" + experiment.Name,
+			Namespace: engineDetails.EngineNamespace,
+		},
+		Status: v1alpha1.ChaosResultStatus{
+			ExperimentStatus: v1alpha1.TestStatus{
+				Verdict: v1alpha1.ResultVerdictAwaited,
+			},
+		},
+	}
+	if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosResults(engineDetails.EngineNamespace).Create(context.Background(), chaosResult, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("chaosresult not created, err: %v", err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(200*time.Millisecond, func() { cancel(ErrEngineStopped) })
+	start := time.Now()
+	chaosResult = experiment.WaitForFinalVerdict(ctx, chaosResult, engineDetails, client)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the wait to stop once the chaosengine is stopped, waited %v", elapsed)
+	}
+	if !isVerdictAwaited(chaosResult) {
+		t.Fatalf("expected the verdict to be still awaited, got %v", chaosResult.Status.ExperimentStatus.Verdict)
+	}
+}
@@ -5,13 +5,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+	"github.com/litmuschaos/chaos-runner/pkg/metrics"
 	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // GetChaosPod gets the chaos experiment pod object launched by the runner
+// If the job controller replaced the chaos pod, the newest active pod is returned
 func GetChaosPod(expDetails *ExperimentDetails, clients ClientSets) (*corev1.Pod, error) {
 	var chaosPodList *corev1.PodList
 	var err error
@@ -24,10 +28,6 @@ func GetChaosPod(expDetails *ExperimentDetails, clients ClientSets) (*corev1.Pod
 			chaosPodList, err = clients.KubeClient.CoreV1().Pods(expDetails.Namespace).List(context.Background(), metav1.ListOptions{LabelSelector: "job-name=" + expDetails.JobName})
 			if err != nil || len(chaosPodList.Items) == 0 {
 				return errors.Errorf("unable to get the chaos pod, error: %v", err)
-			} else if len(chaosPodList.Items) > 1 {
-				// Cases where experiment pod is rescheduled by the job controller due to
-				// issues while the older pod is still not cleaned-up
-				return errors.Errorf("Multiple pods exist with same job-name label")
 			}
 			return nil
 		})
@@ -35,89 +35,220 @@ func GetChaosPod(expDetails *ExperimentDetails, clients ClientSets) (*corev1.Pod
 		return nil, err
 	}
 
-	// Note: We error out upon existence of multiple exp pods for the same experiment
-	// & hence use index [0]
-	chaosPod := &chaosPodList.Items[0]
-	return chaosPod, nil
+	pods := make([]*corev1.Pod, 0, len(chaosPodList.Items))
+	for i := range chaosPodList.Items {
+		pods = append(pods, &chaosPodList.Items[i])
+	}
+	sortPodsByCreationTime(pods)
+	return newestChaosPod(pods), nil
 }
 
-// GetChaosContainerStatus gets status of the chaos container
-func GetChaosContainerStatus(experimentDetails *ExperimentDetails, clients ClientSets) (bool, error) {
-
-	isCompleted := false
-
-	pod, err := GetChaosPod(experimentDetails, clients)
+// WatchChaosContainerForCompletion watches the chaos container for completion
+// It tracks the experiment job and its pods through informers, and patches the chaosengine only once the chaos pod changes
+// The job conditions are the source of truth for the completion, while the newest active pod of the job is followed
+// as the chaos pod, so that the pods replaced by the job controller are recorded as restarts
+// It stops watching once the context is done and returns the cause of the cancellation
+func (engineDetails EngineDetails) WatchChaosContainerForCompletion(ctx context.Context, experiment *ExperimentDetails, clients ClientSets) error {
+	tracker, err := newChaosPodTracker(ctx, experiment, clients)
 	if err != nil {
-		return false, errors.Errorf("unable to get the chaos pod, error: %v", err)
-	}
-	if pod.Status.Phase == corev1.PodSucceeded {
-		return true, nil
+		return err
 	}
+	defer tracker.stop()
+
+	statusCheckTimeout := time.Duration(experiment.StatusCheckTimeout) * time.Second
+	// unsettledSince is the time since when the chaos pod is missing, pending or failed without being replaced yet
+	var unsettledSince time.Time
+	var patchedPodName string
+	// runningPodName is the chaos pod, whose running state is already notified
+	var runningPodName string
+	// scheduledPodName is the chaos pod, whose scheduling latency is already recorded
+	var scheduledPodName string
+	for {
+		// the job is fetched once per change, and the pods of the job are picked by it
+		job, err := tracker.job(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
+			return errors.Errorf("unable to get the experiment job, error: %v", err)
+		}
+		experiment.Timings.observeJob(job)
+		if job != nil {
+			experiment.jobReference = jobReference(job)
+		}
+		// the job controller fails the job and kills the chaos pod once its activeDeadlineSeconds is reached
+		if experiment.ActiveDeadlineSeconds > 0 && isJobDeadlineExceeded(job) {
+			return ErrDeadlineExceeded
+		}
 
-	if pod.Status.Phase == corev1.PodRunning {
-		for _, container := range pod.Status.ContainerStatuses {
-
-			//NOTE: The name of container inside chaos-pod is same as the chaos job name
-			// we only have one container inside chaos pod to inject the chaos
-			// looking the chaos container is completed or not
-			if strings.Contains(container.Name, experimentDetails.JobName) && container.State.Terminated == nil {
-				return false, nil
+		pods, err := tracker.pods(ctx, job)
+		if err != nil {
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
 			}
+			return errors.Errorf("unable to get the chaos pod, error: %v", err)
 		}
-		return true, nil
-	} else if pod.Status.Phase == corev1.PodPending {
-		delay := 2
-		err := retry.
-			Times(uint(experimentDetails.StatusCheckTimeout / delay)).
-			Wait(time.Duration(delay) * time.Second).
-			Try(func(attempt uint) error {
-				pod, err := GetChaosPod(experimentDetails, clients)
-				if err != nil {
-					return errors.Errorf("unable to get the chaos pod, error: %v", err)
+		chaosPod := newestChaosPod(pods)
+		if chaosPod != nil {
+			if patchedPodName != "" && chaosPod.Name != patchedPodName {
+				log.WithContext(ctx).Warnf("chaos pod: %v of Chaos Experiment: %v is replaced by: %v", patchedPodName, experiment.Name, chaosPod.Name)
+				experiment.ExperimentPodRestarted(patchedPodName, chaosPod.Name, engineDetails, clients)
+			}
+			experiment.ChaosPodName = chaosPod.Name
+			experiment.podReference = podReference(chaosPod)
+			experiment.Termination = chaosContainerTermination(chaosPod, experiment.JobName)
+			experiment.Timings.observeChaosPod(chaosPod)
+			// patch the chaosengine only once a new chaos pod is observed
+			if chaosPod.Name != patchedPodName {
+				var expStatus ExperimentStatus
+				expStatus.AwaitedExperimentStatus(experiment.Name, engineDetails.Name, chaosPod.Name)
+				if err := expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, clients); err != nil {
+					return errors.Errorf("unable to patch ChaosEngine in namespace: %v, error: %v", engineDetails.EngineNamespace, err)
 				}
-				if pod.Status.Phase == corev1.PodPending {
-					return errors.Errorf("chaos pod is in %v state", corev1.PodPending)
+				patchedPodName = chaosPod.Name
+			}
+			if latency, ok := chaosPodSchedulingLatency(chaosPod); ok && chaosPod.Name != scheduledPodName {
+				metrics.ObserveChaosPodScheduling(experiment.Name, latency)
+				scheduledPodName = chaosPod.Name
+			}
+			if chaosPod.Status.Phase == corev1.PodRunning && chaosPod.Name != runningPodName {
+				engineDetails.Notify(NotificationPodRunning, experiment, "Chaos Pod "+chaosPod.Name+" for Chaos Experiment: "+experiment.Name+" is running")
+				runningPodName = chaosPod.Name
+			}
+		}
+
+		if condition := getJobCondition(job, batchv1.JobComplete); condition != nil {
+			experiment.Timings.observeCompletion(job)
+			return nil
+		}
+		if condition := getJobCondition(job, batchv1.JobFailed); condition != nil {
+			// report the classified failure of the last chaos pod, if any
+			if chaosPod != nil {
+				if podFailure := classifyPodFailure(chaosPod); podFailure != nil {
+					podFailure.Terminal = true
+					return podFailure
 				}
+			}
+			return errors.Errorf("experiment job: %v is failed, reason: %v, message: %v", experiment.JobName, condition.Reason, condition.Message)
+		}
+
+		var unsettledErr error
+		switch {
+		case chaosPod == nil:
+			unsettledErr = errors.Errorf("unable to get the chaos pod, error: no pod found with job-name label: %v", experiment.JobName)
+		case chaosPod.Status.Phase == corev1.PodFailed:
+			// the job controller may replace the failed chaos pod, according to the backoffLimit of the job
+			unsettledErr = errors.Errorf("status check failed as chaos pod status is %v", chaosPod.Status.Phase)
+			if podFailure := classifyPodFailure(chaosPod); podFailure != nil {
+				unsettledErr = podFailure
+			}
+		default:
+			// fail fast once the chaos pod can't recover, the transient failures are reported once the statusCheckTimeout is reached
+			podFailure := classifyPodFailure(chaosPod)
+			if podFailure != nil && podFailure.Terminal {
+				return podFailure
+			}
+			isCompleted, isSettled, err := getChaosContainerState(chaosPod, experiment.JobName)
+			if err != nil {
+				return err
+			}
+			if isCompleted {
+				experiment.Timings.observeCompletion(job)
 				return nil
-			})
-		if err != nil {
-			return isCompleted, err
+			}
+			switch {
+			case podFailure != nil:
+				unsettledErr = podFailure
+			case !isSettled:
+				unsettledErr = errors.Errorf("chaos pod is in %v state", corev1.PodPending)
+			}
 		}
-	} else if pod.Status.Phase == corev1.PodFailed {
-		return isCompleted, errors.Errorf("status check failed as chaos pod status is %v", pod.Status.Phase)
-	}
 
-	return isCompleted, nil
+		// wait for the next change, bounded by the statusCheckTimeout while the chaos pod is unsettled
+		var timeout <-chan time.Time
+		if unsettledErr == nil {
+			unsettledSince = time.Time{}
+		} else {
+			if unsettledSince.IsZero() {
+				unsettledSince = time.Now()
+			}
+			remaining := statusCheckTimeout - time.Since(unsettledSince)
+			if remaining <= 0 {
+				return unsettledErr
+			}
+			timeout = time.After(remaining)
+		}
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-tracker.changes:
+		case <-timeout:
+		}
+	}
 }
 
-// WatchChaosContainerForCompletion watches the chaos container for completion
-func (engineDetails EngineDetails) WatchChaosContainerForCompletion(experiment *ExperimentDetails, clients ClientSets) error {
-
-	//TODO: use watch rather than checking for status manually.
-	isChaosCompleted := false
-
-	var err error
-	for !isChaosCompleted {
-		isChaosCompleted, err = GetChaosContainerStatus(experiment, clients)
-		if err != nil {
-			return err
+// newestChaosPod returns the newest active pod of the experiment job, or the newest pod if none of them is active
+// The pods are expected to be sorted by their creation time
+func newestChaosPod(pods []*corev1.Pod) *corev1.Pod {
+	for i := len(pods) - 1; i >= 0; i-- {
+		if isPodActive(pods[i]) {
+			return pods[i]
 		}
+	}
+	if len(pods) == 0 {
+		return nil
+	}
+	return pods[len(pods)-1]
+}
 
-		if isChaosCompleted {
-			return nil
+// isPodActive checks whether the pod is neither finished nor being deleted
+func isPodActive(pod *corev1.Pod) bool {
+	return pod.DeletionTimestamp == nil && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+}
+
+// getJobCondition returns the given condition of the job, if it is true
+func getJobCondition(job *batchv1.Job, conditionType batchv1.JobConditionType) *batchv1.JobCondition {
+	if job == nil {
+		return nil
+	}
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == conditionType && job.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return &job.Status.Conditions[i]
 		}
+	}
+	return nil
+}
 
-		var expStatus ExperimentStatus
-		chaosPod, err := GetChaosPod(experiment, clients)
-		if err != nil {
-			return errors.Errorf("unable to get the chaos pod, error: %v", err)
+// getChaosContainerState returns whether the chaos container is completed, and whether the chaos pod
+// is settled, i.e. it isn't pending anymore. It returns an error if the chaos pod is failed
+func getChaosContainerState(pod *corev1.Pod, jobName string) (bool, bool, error) {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return true, true, nil
+	case corev1.PodRunning:
+		for _, container := range pod.Status.ContainerStatuses {
+			//NOTE: The name of container inside chaos-pod is same as the chaos job name
+			// we only have one container inside chaos pod to inject the chaos
+			// looking the chaos container is completed or not
+			if strings.Contains(container.Name, jobName) && container.State.Terminated == nil {
+				return false, true, nil
+			}
 		}
+		return true, true, nil
+	case corev1.PodPending:
+		return false, false, nil
+	case corev1.PodFailed:
+		return false, true, errors.Errorf("status check failed as chaos pod status is %v", pod.Status.Phase)
+	}
+	return false, true, nil
+}
 
-		expStatus.AwaitedExperimentStatus(experiment.Name, engineDetails.Name, chaosPod.Name)
-		if err := expStatus.PatchChaosEngineStatus(engineDetails, clients); err != nil {
-			return errors.Errorf("unable to patch ChaosEngine in namespace: %v, error: %v", engineDetails.EngineNamespace, err)
+// chaosPodSchedulingLatency returns the latency between the creation and the scheduling of the chaos pod, once it is scheduled
+func chaosPodSchedulingLatency(pod *corev1.Pod) (time.Duration, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+			return condition.LastTransitionTime.Sub(pod.CreationTimestamp.Time), true
 		}
-		time.Sleep(2 * time.Second)
 	}
-	return nil
+	return 0, false
 }
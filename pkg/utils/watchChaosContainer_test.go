@@ -2,11 +2,21 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
+	litmusFakeClientset "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned/fake"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestGetChaosPod(t *testing.T) {
@@ -73,14 +83,15 @@ func TestGetChaosPod(t *testing.T) {
 			},
 			isErr: true,
 		},
-		"Test Negative-2": {
+		"Test Positive-2": {
 			chaospod: v1.Pod{
 				TypeMeta: metav1.TypeMeta{
 					Kind:       "Pod",
 					APIVersion: "v1",
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "fake-chaos-pod",
+					Name:              "fake-chaos-pod",
+					CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
 					Labels: map[string]string{
 						"app":      "myapp",
 						"job-name": experiment.JobName,
@@ -102,7 +113,8 @@ func TestGetChaosPod(t *testing.T) {
 					APIVersion: "v1",
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "fake-chaos-pod-2",
+					Name:              "fake-chaos-pod-2",
+					CreationTimestamp: metav1.NewTime(time.Now()),
 					Labels: map[string]string{
 						"app":      "myapp",
 						"job-name": experiment.JobName,
@@ -137,129 +149,16 @@ func TestGetChaosPod(t *testing.T) {
 				}
 			}
 
-			_, err = GetChaosPod(&experiment, client)
-			if err != nil && !mock.isErr && !mock.isSecondTest {
-				t.Fatalf("%v test failed, fail to get the chaos pod, err: %v", name, err)
-			} else if err == nil && mock.isErr && mock.isSecondTest {
-				t.Fatalf("%v test failed, the err should not be nil", name)
-			}
-		})
-	}
-}
-
-func TestGetChaosContainerStatus(t *testing.T) {
-	fakeExperimentImage := "fake-experiment-image"
-	experiment := ExperimentDetails{
-		Name:               "Fake-Exp-Name",
-		Namespace:          "Fake NameSpace",
-		JobName:            "fake-jobs-name-12345",
-		StatusCheckTimeout: 2,
-	}
-	tests := map[string]struct {
-		chaospod v1.Pod
-		isErr    bool
-	}{
-		"Test Positive-1": {
-			chaospod: v1.Pod{
-				TypeMeta: metav1.TypeMeta{
-					Kind:       "Pod",
-					APIVersion: "v1",
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "fake-chaos-pod",
-					Labels: map[string]string{
-						"app":      "myapp",
-						"job-name": experiment.JobName,
-					},
-				},
-				Spec: v1.PodSpec{
-					Containers: []v1.Container{
-						{
-							Name:      experiment.JobName,
-							Image:     fakeExperimentImage,
-							Resources: v1.ResourceRequirements{},
-						},
-					},
-				},
-			},
-			isErr: false,
-		},
-		"Test Negative-1": {
-			chaospod: v1.Pod{
-				TypeMeta: metav1.TypeMeta{
-					Kind:       "Pod",
-					APIVersion: "v1",
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "fake-chaos-pod",
-					Labels: map[string]string{
-						"app":      "myapp",
-						"job-name": experiment.JobName,
-					},
-				},
-				Spec: v1.PodSpec{
-					Containers: []v1.Container{
-						{
-							Name:      "wrong-container-name",
-							Image:     fakeExperimentImage,
-							Resources: v1.ResourceRequirements{},
-						},
-					},
-				},
-			},
-			isErr: true,
-		},
-		"Test Negative-2": {
-			chaospod: v1.Pod{
-				TypeMeta: metav1.TypeMeta{
-					Kind:       "Pod",
-					APIVersion: "v1",
-				},
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "fake-chaos-pod",
-					Labels: map[string]string{
-						"app":      "myapp",
-						"job-name": experiment.JobName,
-					},
-				},
-				Spec: v1.PodSpec{
-					Containers: []v1.Container{
-						{
-							Name:      experiment.JobName,
-							Image:     fakeExperimentImage,
-							Resources: v1.ResourceRequirements{},
-						},
-					},
-				},
-				Status: v1.PodStatus{
-					Phase: v1.PodPending,
-				},
-			},
-			isErr: true,
-		},
-	}
-
-	for name, mock := range tests {
-		t.Run(name, func(t *testing.T) {
-			client := CreateFakeClient(t)
-
-			var ns string
-			if !mock.isErr {
-				ns = experiment.Namespace
-			} else {
-				ns = "wrong-ns"
-			}
-			_, err := client.KubeClient.CoreV1().Pods(ns).Create(context.Background(), &mock.chaospod, metav1.CreateOptions{})
-			if err != nil {
-				t.Fatalf("fail to create chaos pod for %v test, err: %v", name, err)
-			}
-
-			_, err = GetChaosContainerStatus(&experiment, client)
+			chaosPod, err := GetChaosPod(&experiment, client)
 			if err != nil && !mock.isErr {
 				t.Fatalf("%v test failed, fail to get the chaos pod, err: %v", name, err)
 			} else if err == nil && mock.isErr {
 				t.Fatalf("%v test failed, the err should not be nil", name)
 			}
+			// the newest pod is followed once the job controller replaced the chaos pod
+			if mock.isSecondTest && chaosPod.Name != mock.chaospod2.Name {
+				t.Fatalf("%v test failed, expected the chaos pod to be %v, got %v", name, mock.chaospod2.Name, chaosPod.Name)
+			}
 		})
 	}
 }
@@ -401,7 +300,7 @@ func TestWatchChaosContainerForCompletion(t *testing.T) {
 				t.Fatalf("fail to create chaos pod for %v test, err: %v", name, err)
 			}
 
-			err = engineDetails.WatchChaosContainerForCompletion(&experiment, client)
+			err = engineDetails.WatchChaosContainerForCompletion(context.Background(), &experiment, client)
 			if err != nil && !mock.isErr {
 				t.Fatalf("%v failed, err: %v", name, err)
 			} else if err == nil && mock.isErr {
@@ -410,3 +309,305 @@ func TestWatchChaosContainerForCompletion(t *testing.T) {
 		})
 	}
 }
+
+func TestWatchChaosContainerForTransitions(t *testing.T) {
+	fakeNamespace := "Fake NameSpace"
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: fakeNamespace,
+	}
+
+	tests := map[string]struct {
+		phase            v1.PodPhase
+		isWatchForbidden bool
+		isJobGetFailed   bool
+		isErr            bool
+		expectedPatches  int
+		completeAfterRun bool
+	}{
+		"Test Positive-1": {
+			phase:            v1.PodRunning,
+			completeAfterRun: true,
+			isErr:            false,
+			expectedPatches:  1,
+		},
+		"Test Positive-2": {
+			phase:            v1.PodRunning,
+			isWatchForbidden: true,
+			completeAfterRun: true,
+			isErr:            false,
+			expectedPatches:  1,
+		},
+		"Test Negative-1": {
+			phase:           v1.PodPending,
+			isErr:           true,
+			expectedPatches: 1,
+		},
+		"Test Negative-2": {
+			phase:            v1.PodRunning,
+			isWatchForbidden: true,
+			isJobGetFailed:   true,
+			isErr:            true,
+			expectedPatches:  0,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			experiment := ExperimentDetails{
+				Name:               "Fake-Exp-Name",
+				Namespace:          fakeNamespace,
+				JobName:            "fake-jobs-name-12345",
+				StatusCheckTimeout: 1,
+			}
+			client := CreateFakeClient(t)
+			if mock.isWatchForbidden {
+				// the service accounts of the experiments don't grant the watch verb on the jobs and the pods
+				client.KubeClient.(*fake.Clientset).PrependWatchReactor("*", func(action k8stesting.Action) (bool, watch.Interface, error) {
+					return true, nil, k8serrors.NewForbidden(action.GetResource().GroupResource(), "", errors.New("watch is not granted"))
+				})
+			}
+			if mock.isJobGetFailed {
+				// the polled job is unavailable, which must not be taken for a missing job
+				client.KubeClient.(*fake.Clientset).PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, k8serrors.NewInternalError(errors.New("etcdserver: request timed out"))
+				})
+			}
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Status: v1alpha1.ChaosEngineStatus{
+					Experiments: []v1alpha1.ExperimentStatuses{
+						{
+							Name: experiment.Name,
+						},
+					},
+				},
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(fakeNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			chaosPod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "fake-chaos-pod",
+					Labels: map[string]string{"job-name": experiment.JobName},
+				},
+				Status: v1.PodStatus{
+					Phase: mock.phase,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name:  experiment.JobName,
+							State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+						},
+					},
+				},
+			}
+			if _, err := client.KubeClient.CoreV1().Pods(fakeNamespace).Create(context.Background(), chaosPod, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("fail to create chaos pod for %v test, err: %v", name, err)
+			}
+
+			if mock.completeAfterRun {
+				// the chaos container completes once the pods are watched and the running chaos pod is patched into the chaosengine
+				watched := notifyOnAction(&client.KubeClient.(*fake.Clientset).Fake, "watch", "pods")
+				patched := notifyOnAction(&client.LitmusClient.(*litmusFakeClientset.Clientset).Fake, "patch", "chaosengines")
+				go func() {
+					// the pods are polled instead, once their watch is forbidden
+					if !mock.isWatchForbidden {
+						<-watched
+					}
+					<-patched
+					chaosPod.Status.ContainerStatuses[0].State = v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}}
+					if _, err := client.KubeClient.CoreV1().Pods(fakeNamespace).Update(context.Background(), chaosPod, metav1.UpdateOptions{}); err != nil {
+						t.Errorf("fail to update chaos pod for %v test, err: %v", name, err)
+					}
+				}()
+			}
+
+			err := engineDetails.WatchChaosContainerForCompletion(context.Background(), &experiment, client)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			// the chaos pod is only observed along with its job
+			if !mock.isJobGetFailed && experiment.ChaosPodName != chaosPod.Name {
+				t.Fatalf("Test %q failed: expected chaos pod name is %v, got %v", name, chaosPod.Name, experiment.ChaosPodName)
+			}
+			patches := 0
+			for _, action := range client.LitmusClient.(*litmusFakeClientset.Clientset).Actions() {
+				if action.GetVerb() == "patch" && action.GetResource().Resource == "chaosengines" {
+					patches++
+				}
+			}
+			if patches != mock.expectedPatches {
+				t.Fatalf("Test %q failed: expected the chaosengine to be patched %v times, got %v", name, mock.expectedPatches, patches)
+			}
+		})
+	}
+}
+
+func TestWatchChaosContainerForPodReplacement(t *testing.T) {
+	fakeNamespace := "Fake NameSpace"
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: fakeNamespace,
+	}
+
+	tests := map[string]struct {
+		jobCondition batchv1.JobConditionType
+		isErr        bool
+	}{
+		"Test Positive-1": {
+			jobCondition: batchv1.JobComplete,
+			isErr:        false,
+		},
+		"Test Negative-1": {
+			jobCondition: batchv1.JobFailed,
+			isErr:        true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			experiment := ExperimentDetails{
+				Name:               "Fake-Exp-Name",
+				Namespace:          fakeNamespace,
+				JobName:            "fake-jobs-name-12345",
+				StatusCheckTimeout: 5,
+			}
+			client := CreateFakeClient(t)
+			chaosEngine := &v1alpha1.ChaosEngine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      engineDetails.Name,
+					Namespace: engineDetails.EngineNamespace,
+				},
+				Status: v1alpha1.ChaosEngineStatus{
+					Experiments: []v1alpha1.ExperimentStatuses{
+						{
+							Name: experiment.Name,
+						},
+					},
+				},
+			}
+			if _, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(fakeNamespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("engine not created for %v test, err: %v", name, err)
+			}
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      experiment.JobName,
+					Namespace: fakeNamespace,
+					UID:       types.UID("fake-job-uid"),
+				},
+			}
+			if _, err := client.KubeClient.BatchV1().Jobs(fakeNamespace).Create(context.Background(), job, metav1.CreateOptions{}); err != nil {
+				t.Fatalf("fail to create exp job for %v test, err: %v", name, err)
+			}
+			isController := true
+			newChaosPod := func(podName string, creationTime time.Time, owner types.UID) *v1.Pod {
+				return &v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              podName,
+						Labels:            map[string]string{"job-name": experiment.JobName},
+						CreationTimestamp: metav1.NewTime(creationTime),
+						OwnerReferences:   []metav1.OwnerReference{{Kind: "Job", Name: experiment.JobName, UID: owner, Controller: &isController}},
+					},
+					Status: v1.PodStatus{
+						Phase: v1.PodRunning,
+						ContainerStatuses: []v1.ContainerStatus{
+							{
+								Name:  experiment.JobName,
+								State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+							},
+						},
+					},
+				}
+			}
+			oldPod := newChaosPod("fake-chaos-pod-old", time.Now().Add(-time.Minute), job.UID)
+			// the pod left over by another job with the same name is ignored
+			staleJobPod := newChaosPod("fake-chaos-pod-stale", time.Now().Add(time.Minute), types.UID("stale-job-uid"))
+			for _, pod := range []*v1.Pod{oldPod, staleJobPod} {
+				if _, err := client.KubeClient.CoreV1().Pods(fakeNamespace).Create(context.Background(), pod, metav1.CreateOptions{}); err != nil {
+					t.Fatalf("fail to create chaos pod for %v test, err: %v", name, err)
+				}
+			}
+
+			// every chaos pod is patched into the chaosengine once it is observed, after the job and its pods are watched
+			watchedPods := notifyOnAction(&client.KubeClient.(*fake.Clientset).Fake, "watch", "pods")
+			watchedJobs := notifyOnAction(&client.KubeClient.(*fake.Clientset).Fake, "watch", "jobs")
+			patched := notifyOnAction(&client.LitmusClient.(*litmusFakeClientset.Clientset).Fake, "patch", "chaosengines")
+			go func() {
+				// the node of the chaos pod is lost, the job controller replaces it
+				<-watchedPods
+				<-watchedJobs
+				<-patched
+				oldPod.Status.Phase = v1.PodFailed
+				if _, err := client.KubeClient.CoreV1().Pods(fakeNamespace).Update(context.Background(), oldPod, metav1.UpdateOptions{}); err != nil {
+					t.Errorf("fail to update chaos pod for %v test, err: %v", name, err)
+				}
+				if _, err := client.KubeClient.CoreV1().Pods(fakeNamespace).Create(context.Background(), newChaosPod("fake-chaos-pod-new", time.Now(), job.UID), metav1.CreateOptions{}); err != nil {
+					t.Errorf("fail to create chaos pod for %v test, err: %v", name, err)
+				}
+				<-patched
+				job.Status.Conditions = []batchv1.JobCondition{{Type: mock.jobCondition, Status: v1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+				if _, err := client.KubeClient.BatchV1().Jobs(fakeNamespace).UpdateStatus(context.Background(), job, metav1.UpdateOptions{}); err != nil {
+					t.Errorf("fail to update exp job for %v test, err: %v", name, err)
+				}
+			}()
+
+			err := engineDetails.WatchChaosContainerForCompletion(context.Background(), &experiment, client)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			if experiment.ChaosPodName != "fake-chaos-pod-new" {
+				t.Fatalf("Test %q failed: expected the chaos pod to be fake-chaos-pod-new, got %v", name, experiment.ChaosPodName)
+			}
+			client.EventRecorder.Flush(EventsFlushTimeout)
+			events, err := client.KubeClient.CoreV1().Events(fakeNamespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("fail to list the events for %v test, err: %v", name, err)
+			}
+			isRestartEvent := false
+			for _, event := range events.Items {
+				if event.Reason == ExperimentPodRestartedReason {
+					isRestartEvent = true
+				}
+			}
+			if !isRestartEvent {
+				t.Fatalf("Test %q failed: expected %v event to be generated", name, ExperimentPodRestartedReason)
+			}
+		})
+	}
+}
+
+func TestChaosPodSchedulingLatency(t *testing.T) {
+	creationTime := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	tests := map[string]struct {
+		conditions      []v1.PodCondition
+		expectedLatency time.Duration
+		isScheduled     bool
+	}{
+		"Test Positive-1": {
+			conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(creationTime.Add(3 * time.Second))},
+			},
+			expectedLatency: 3 * time.Second,
+			isScheduled:     true,
+		},
+		"Test Negative-1": {
+			conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: v1.PodReasonUnschedulable},
+			},
+			isScheduled: false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(creationTime)},
+				Status:     v1.PodStatus{Conditions: mock.conditions},
+			}
+			latency, ok := chaosPodSchedulingLatency(pod)
+			if ok != mock.isScheduled || latency != mock.expectedLatency {
+				t.Fatalf("Test %q failed: expected the latency to be %v, got %v", name, mock.expectedLatency, latency)
+			}
+		})
+	}
+}
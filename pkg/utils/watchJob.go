@@ -2,14 +2,20 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientretry "k8s.io/client-go/util/retry"
 
 	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	"github.com/litmuschaos/chaos-runner/pkg/log"
+	"github.com/litmuschaos/chaos-runner/pkg/metrics"
 	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 )
 
@@ -28,39 +34,135 @@ func checkStatusListForExp(status []v1alpha1.ExperimentStatuses, ExperimentName
 }
 
 // GetChaosEngine returns chaosEngine Object
-func (engineDetails EngineDetails) GetChaosEngine(clients ClientSets) (*v1alpha1.ChaosEngine, error) {
+// The retries stop once the context is done
+func (engineDetails EngineDetails) GetChaosEngine(ctx context.Context, clients ClientSets) (*v1alpha1.ChaosEngine, error) {
 	var engine *v1alpha1.ChaosEngine
-	if err := retry.
+	var err error
+	_ = retry.
 		Times(uint(180)).
 		Wait(time.Duration(2)).
 		Try(func(attempt uint) error {
-			engine, err = clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
-			if err != nil {
-				return errors.Errorf("unable to get ChaosEngine name: %v, in namespace: %v, error: %v", engineDetails.Name, engineDetails.EngineNamespace, err)
+			engine, err = clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Get(ctx, engineDetails.Name, metav1.GetOptions{})
+			if ctx.Err() != nil {
+				return nil
 			}
-			return nil
-		}); err != nil {
-		return nil, err
+			return err
+		})
+	if err != nil {
+		return nil, errors.Errorf("unable to get ChaosEngine name: %v, in namespace: %v, error: %v", engineDetails.Name, engineDetails.EngineNamespace, err)
 	}
 	return engine, nil
 }
 
 // PatchChaosEngineStatus updates ChaosEngine with Experiment Status
-func (expStatus *ExperimentStatus) PatchChaosEngineStatus(engineDetails EngineDetails, clients ClientSets) error {
+// It only replaces the status entry of the given experiment through a JSON patch, guarded by the name of the entry,
+// so that neither the experiments running in parallel nor the other actors updating the chaosengine are overwritten
+func (expStatus *ExperimentStatus) PatchChaosEngineStatus(ctx context.Context, engineDetails EngineDetails, clients ClientSets) error {
+	return retryOnStatusPatchFailure(func() error {
+		expEngine, err := engineDetails.GetChaosEngine(ctx, clients)
+		if err != nil {
+			return err
+		}
+		experimentIndex := checkStatusListForExp(expEngine.Status.Experiments, expStatus.Name)
+		if experimentIndex == -1 {
+			return errors.Errorf("unable to find the status for Experiment: %v in ChaosEngine: %v", expStatus.Name, expEngine.Name)
+		}
+		return engineDetails.patchChaosEngineStatus(ctx, replaceExperimentStatusPatch(experimentIndex, v1alpha1.ExperimentStatuses(*expStatus)), clients)
+	})
+}
+
+// jsonPatchOperation is a single operation of a JSON patch, as defined by RFC 6902
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
 
-	expEngine, err := engineDetails.GetChaosEngine(clients)
+// replaceExperimentStatusPatch returns the operations replacing the status entry at the given index,
+// which fail if the entry at the index doesn't belong to the same experiment anymore
+func replaceExperimentStatusPatch(index int, status v1alpha1.ExperimentStatuses) []jsonPatchOperation {
+	path := "/status/experiments/" + strconv.Itoa(index)
+	return []jsonPatchOperation{
+		{Op: "test", Path: path + "/name", Value: status.Name},
+		{Op: "replace", Path: path, Value: status},
+	}
+}
+
+// SetStatusSubresource checks once through the discovery, whether the chaosengine crd defines the status subresource,
+// which the status patches are applied through. The status subresource is assumed, if the discovery fails
+func (engineDetails *EngineDetails) SetStatusSubresource(clients ClientSets) *EngineDetails {
+	engineDetails.StatusSubresource = true
+	resources, err := clients.KubeClient.Discovery().ServerResourcesForGroupVersion(v1alpha1.SchemeGroupVersion.String())
 	if err != nil {
-		return err
+		log.Warnf("unable to discover the subresources of the chaosengines, assuming the status subresource, error: %v", err)
+		return engineDetails
+	}
+	engineDetails.StatusSubresource = false
+	for _, resource := range resources.APIResources {
+		if resource.Name == "chaosengines/status" {
+			engineDetails.StatusSubresource = true
+		}
 	}
-	experimentIndex := checkStatusListForExp(expEngine.Status.Experiments, expStatus.Name)
-	if experimentIndex == -1 {
-		return errors.Errorf("unable to find the status for Experiment: %v in ChaosEngine: %v", expStatus.Name, expEngine.Name)
+	return engineDetails
+}
+
+// patchChaosEngineStatus applies the JSON patch to the chaosengine status, through the status subresource where available
+func (engineDetails EngineDetails) patchChaosEngineStatus(ctx context.Context, operations []jsonPatchOperation, clients ClientSets) error {
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return errors.Errorf("unable to marshal the status patch, error: %v", err)
 	}
-	expEngine.Status.Experiments[experimentIndex] = v1alpha1.ExperimentStatuses(*expStatus)
-	if _, err := clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Update(context.Background(), expEngine, metav1.UpdateOptions{}); err != nil {
-		return err
+	var subresources []string
+	// the status is a part of the main resource, if the crd doesn't define the status subresource
+	if engineDetails.StatusSubresource {
+		subresources = append(subresources, "status")
 	}
-	return nil
+	_, err = clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Patch(ctx, engineDetails.Name, types.JSONPatchType, patch, metav1.PatchOptions{}, subresources...)
+	return err
+}
+
+// isStatusPatchTestFailure checks whether the status patch failed its test operation, as the entry it patches was moved by another actor
+// The api server rejects such a patch as invalid without any field causes, unlike the patches failing the validation of the crd schema
+func isStatusPatchTestFailure(err error) bool {
+	var apiStatus k8serrors.APIStatus
+	if !k8serrors.IsInvalid(err) || !errors.As(err, &apiStatus) {
+		return false
+	}
+	if details := apiStatus.Status().Details; details != nil {
+		for _, cause := range details.Causes {
+			if cause.Field != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// retryOnStatusPatchFailure retries the status patch once it conflicts with another change of the chaosengine,
+// or once the entry it patches was moved by another actor, failing the test operation of the patch
+// Any other invalid patch is returned right away
+func retryOnStatusPatchFailure(fn func() error) error {
+	return clientretry.OnError(clientretry.DefaultRetry, func(err error) bool {
+		if k8serrors.IsConflict(err) || isStatusPatchTestFailure(err) {
+			metrics.ObserveStatusPatchConflict()
+			return true
+		}
+		return false
+	}, fn)
+}
+
+// PatchChaosEngineAnnotations merges the given annotations into the chaosengine metadata
+func (engineDetails EngineDetails) PatchChaosEngineAnnotations(ctx context.Context, annotations map[string]string, clients ClientSets) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return errors.Errorf("unable to marshal the annotations patch, error: %v", err)
+	}
+	_, err = clients.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(engineDetails.EngineNamespace).Patch(ctx, engineDetails.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
 }
 
 // GetResultName returns the resultName using the experimentName and engine Name
@@ -84,12 +186,42 @@ func (expDetails *ExperimentDetails) GetChaosResult(engineDetails EngineDetails,
 	return expResult, nil
 }
 
+// SetResultDetails sets the fail step and the probe success percentage of the experiment from its chaosresult
+// The fail step is reported by the experiment as the reason of the error output
+func (expDetails *ExperimentDetails) SetResultDetails(chaosResult *v1alpha1.ChaosResult) *ExperimentDetails {
+	expDetails.ProbeSuccessPercentage = chaosResult.Status.ExperimentStatus.ProbeSuccessPercentage
+	if errorOutput := chaosResult.Status.ExperimentStatus.ErrorOutput; errorOutput != nil {
+		expDetails.FailStep = errorOutput.Reason
+	}
+	return expDetails
+}
+
 // UpdateEngineWithResult will update the result in chaosEngine
 // And will delete job if jobCleanUpPolicy is set to "delete"
-func (engineDetails EngineDetails) UpdateEngineWithResult(experiment *ExperimentDetails, clients ClientSets) error {
+// The termination of the chaos container is recorded along with the verdict, even if the chaosresult is missing.
+// The chaosresult verdict is awaited till the verdict timeout, and the termination log of the chaos container
+// is used as the verdict source, if the chaosresult is missing or still awaited afterwards
+// It returns the cause of the cancellation, if the run context is done while awaiting the verdict
+func (engineDetails EngineDetails) UpdateEngineWithResult(ctx context.Context, experiment *ExperimentDetails, clients ClientSets) error {
+	engineDetails.RecordTermination(experiment, clients)
+	engineDetails.RecordTimings(experiment, clients)
+
 	// Getting the Experiment Result Name
 	chaosResult, err := experiment.GetChaosResult(engineDetails, clients)
-	if err != nil {
+	if err == nil {
+		chaosResult = experiment.WaitForFinalVerdict(ctx, chaosResult, engineDetails, clients)
+		if isVerdictAwaited(chaosResult) && ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+	}
+	var terminationResult *TerminationResult
+	if err != nil || isVerdictAwaited(chaosResult) {
+		terminationResult = experiment.terminationResult()
+	}
+	if err != nil && terminationResult == nil {
+		if experiment.Termination != nil {
+			return errors.Errorf("%v, chaos container terminated with %v", err, experiment.Termination.String())
+		}
 		return err
 	}
 
@@ -98,8 +230,25 @@ func (engineDetails EngineDetails) UpdateEngineWithResult(experiment *Experiment
 	if err != nil {
 		return errors.Errorf("unable to get the chaos pod, error: %v", err)
 	}
-	currExpStatus.CompletedExperimentStatus(chaosResult, engineDetails.Name, chaosPod.Name)
-	if err = currExpStatus.PatchChaosEngineStatus(engineDetails, clients); err != nil {
+	if terminationResult != nil {
+		log.WithContext(ctx).Infof("using the termination log of Chaos Experiment: %v as verdict source, verdict: %v, failStep: %v", experiment.Name, terminationResult.Verdict, terminationResult.FailStep)
+		annotations := map[string]string{
+			experimentAnnotation(experiment.Name, VerdictSourceAnnotation): TerminationLogVerdictSource,
+		}
+		if err := engineDetails.PatchChaosEngineAnnotations(ctx, annotations, clients); err != nil {
+			log.WithContext(ctx).Errorf("unable to record the verdict source of Chaos Experiment: %v, error: %v", experiment.Name, err)
+		}
+		currExpStatus.TerminationResultExperimentStatus(terminationResult, experiment.Name, engineDetails.Name, chaosPod.Name)
+		experiment.FailStep = terminationResult.FailStep
+		experiment.ProbeSuccessPercentage = terminationResult.ProbeSuccessPercentage
+	} else if isVerdictAwaited(chaosResult) {
+		return engineDetails.VerdictTimeoutExperiment(experiment, chaosPod.Name, clients)
+	} else {
+		currExpStatus.CompletedExperimentStatus(chaosResult, engineDetails.Name, chaosPod.Name)
+		experiment.SetResultDetails(chaosResult)
+	}
+	experiment.Verdict = currExpStatus.Verdict
+	if err = currExpStatus.PatchChaosEngineStatus(context.Background(), engineDetails, clients); err != nil {
 		return err
 	}
 
@@ -107,27 +256,32 @@ func (engineDetails EngineDetails) UpdateEngineWithResult(experiment *Experiment
 }
 
 // DeleteJobAccordingToJobCleanUpPolicy deletes the chaosExperiment Job according to jobCleanUpPolicy
-func (engineDetails EngineDetails) DeleteJobAccordingToJobCleanUpPolicy(experiment *ExperimentDetails, clients ClientSets) (v1alpha1.CleanUpPolicy, error) {
+func (engineDetails EngineDetails) DeleteJobAccordingToJobCleanUpPolicy(ctx context.Context, experiment *ExperimentDetails, clients ClientSets) (v1alpha1.CleanUpPolicy, error) {
 
-	expEngine, err := engineDetails.GetChaosEngine(clients)
+	expEngine, err := engineDetails.GetChaosEngine(ctx, clients)
 	if err != nil {
 		return "", err
 	}
 
 	switch expEngine.Spec.JobCleanUpPolicy {
 	case v1alpha1.CleanUpPolicyDelete:
-		log.Infof("deleting the job as jobCleanPolicy is set to %s", expEngine.Spec.JobCleanUpPolicy)
-		deletePolicy := metav1.DeletePropagationForeground
-		if deleteJobErr := clients.KubeClient.BatchV1().Jobs(experiment.Namespace).Delete(context.Background(), experiment.JobName, metav1.DeleteOptions{
-			PropagationPolicy: &deletePolicy,
-		}); deleteJobErr != nil {
-			return "", errors.Errorf("unable to delete ChaosExperiment Job name: %v, in namespace: %v, error: %v", experiment.JobName, experiment.Namespace, deleteJobErr)
+		log.WithContext(ctx).Infof("deleting the job as jobCleanPolicy is set to %s", expEngine.Spec.JobCleanUpPolicy)
+		if err := experiment.DeleteJob(ctx, clients); err != nil {
+			return "", errors.Errorf("unable to delete ChaosExperiment Job name: %v, in namespace: %v, error: %v", experiment.JobName, experiment.Namespace, err)
 		}
-		log.Infof("%v job is deleted successfully", experiment.JobName)
+		log.WithContext(ctx).Infof("%v job is deleted successfully", experiment.JobName)
 	case v1alpha1.CleanUpPolicyRetain, "":
-		log.Infof("[skip]: skipping the job deletion as jobCleanUpPolicy is set to {%s}", expEngine.Spec.JobCleanUpPolicy)
+		log.WithContext(ctx).Infof("[skip]: skipping the job deletion as jobCleanUpPolicy is set to {%s}", expEngine.Spec.JobCleanUpPolicy)
 	default:
 		return expEngine.Spec.JobCleanUpPolicy, fmt.Errorf("%s jobCleanUpPolicy not supported", expEngine.Spec.JobCleanUpPolicy)
 	}
 	return expEngine.Spec.JobCleanUpPolicy, nil
 }
+
+// DeleteJob deletes the chaosExperiment Job along with its pods
+func (expDetails *ExperimentDetails) DeleteJob(ctx context.Context, clients ClientSets) error {
+	deletePolicy := metav1.DeletePropagationForeground
+	return clients.KubeClient.BatchV1().Jobs(expDetails.Namespace).Delete(ctx, expDetails.JobName, metav1.DeleteOptions{
+		PropagationPolicy: &deletePolicy,
+	})
+}
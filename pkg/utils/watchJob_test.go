@@ -3,13 +3,20 @@ package utils
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sync"
 	"testing"
 
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/watch"
+	fakediscovery "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/litmuschaos/chaos-operator/api/litmuschaos/v1alpha1"
 	litmusFakeClientset "github.com/litmuschaos/chaos-operator/pkg/client/clientset/versioned/fake"
@@ -88,7 +95,7 @@ func TestPatchChaosEngineStatus(t *testing.T) {
 				t.Fatalf("engine not created for %v test, err: %v", name, err)
 			}
 
-			err = expStatus.PatchChaosEngineStatus(engineDetails, client)
+			err = expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, client)
 			if !mock.isErr && err != nil {
 				t.Fatalf("fail to patch the engine status for %v test, err: %v", name, err)
 			}
@@ -116,6 +123,210 @@ func TestPatchChaosEngineStatus(t *testing.T) {
 	}
 }
 
+func TestPatchChaosEngineStatusInParallel(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	experiments := []string{"exp-1", "exp-2", "exp-3", "exp-4"}
+
+	chaosEngine := &v1alpha1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.Name,
+			Namespace: engineDetails.EngineNamespace,
+		},
+	}
+	for _, exp := range experiments {
+		chaosEngine.Status.Experiments = append(chaosEngine.Status.Experiments, v1alpha1.ExperimentStatuses{
+			Name:   exp,
+			Status: v1alpha1.ExperimentStatusWaiting,
+		})
+	}
+
+	client := CreateFakeClient(t)
+	_, err := client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(chaosEngine.Namespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("engine not created, err: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, exp := range experiments {
+		wg.Add(1)
+		go func(expName string) {
+			defer wg.Done()
+			var expStatus ExperimentStatus
+			expStatus.AwaitedExperimentStatus(expName, engineDetails.Name, expName+"-pod")
+			if err := expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, client); err != nil {
+				t.Errorf("fail to patch the engine status for %v experiment, err: %v", expName, err)
+			}
+		}(exp)
+	}
+	wg.Wait()
+
+	chaosEngine, err = client.LitmusClient.LitmuschaosV1alpha1().ChaosEngines(chaosEngine.Namespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("fail to get chaos engine after status patch, err: %v", err)
+	}
+	for _, expStatus := range chaosEngine.Status.Experiments {
+		if expStatus.Status != v1alpha1.ExperimentStatusRunning || expStatus.ExpPod != expStatus.Name+"-pod" {
+			t.Fatalf("expected status of %v experiment to be patched, got %v", expStatus.Name, expStatus)
+		}
+	}
+}
+
+func TestPatchChaosEngineStatusOnConcurrentChange(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	chaosEngine := &v1alpha1.ChaosEngine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      engineDetails.Name,
+			Namespace: engineDetails.EngineNamespace,
+		},
+		Status: v1alpha1.ChaosEngineStatus{
+			Experiments: []v1alpha1.ExperimentStatuses{
+				{
+					Name:   "exp-1",
+					Status: v1alpha1.ExperimentStatusWaiting,
+				},
+			},
+		},
+	}
+
+	client := CreateFakeClient(t)
+	litmusClient := client.LitmusClient.(*litmusFakeClientset.Clientset)
+	if _, err := litmusClient.LitmuschaosV1alpha1().ChaosEngines(chaosEngine.Namespace).Create(context.Background(), chaosEngine, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("engine not created, err: %v", err)
+	}
+	// another actor inserts a status entry and labels the chaosengine, right before the first patch of the runner is applied
+	isChanged := false
+	litmusClient.PrependReactor("patch", "chaosengines", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if isChanged {
+			return false, nil, nil
+		}
+		isChanged = true
+		engine, err := litmusClient.Tracker().Get(action.GetResource(), action.GetNamespace(), engineDetails.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		changedEngine := engine.(*v1alpha1.ChaosEngine).DeepCopy()
+		changedEngine.Labels = map[string]string{"changed-by": "operator"}
+		changedEngine.Status.Experiments = append([]v1alpha1.ExperimentStatuses{{Name: "exp-0"}}, changedEngine.Status.Experiments...)
+		if err := litmusClient.Tracker().Update(action.GetResource(), changedEngine, action.GetNamespace()); err != nil {
+			return true, nil, err
+		}
+		return true, nil, k8serrors.NewConflict(action.GetResource().GroupResource(), engineDetails.Name, fmt.Errorf("the object has been modified"))
+	})
+
+	var expStatus ExperimentStatus
+	expStatus.AwaitedExperimentStatus("exp-1", engineDetails.Name, "exp-1-pod")
+	if err := expStatus.PatchChaosEngineStatus(context.Background(), engineDetails, client); err != nil {
+		t.Fatalf("fail to patch the engine status, err: %v", err)
+	}
+
+	chaosEngine, err := litmusClient.LitmuschaosV1alpha1().ChaosEngines(chaosEngine.Namespace).Get(context.Background(), engineDetails.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("fail to get chaos engine after status patch, err: %v", err)
+	}
+	if chaosEngine.Labels["changed-by"] != "operator" {
+		t.Fatalf("expected the change of the other actor to be retained, got labels %v", chaosEngine.Labels)
+	}
+	if len(chaosEngine.Status.Experiments) != 2 || chaosEngine.Status.Experiments[0].Name != "exp-0" {
+		t.Fatalf("expected the status entry of the other actor to be retained, got %v", chaosEngine.Status.Experiments)
+	}
+	if chaosEngine.Status.Experiments[1].Status != v1alpha1.ExperimentStatusRunning {
+		t.Fatalf("expected status of exp-1 experiment to be patched, got %v", chaosEngine.Status.Experiments[1])
+	}
+}
+
+func TestRetryOnStatusPatchFailure(t *testing.T) {
+	groupResource := v1alpha1.SchemeGroupVersion.WithResource("chaosengines").GroupResource()
+	tests := map[string]struct {
+		err              error
+		expectedAttempts int
+	}{
+		"Test Positive-1": {
+			err:              k8serrors.NewConflict(groupResource, "fake-engine", fmt.Errorf("the object has been modified")),
+			expectedAttempts: 2,
+		},
+		"Test Positive-2": {
+			err:              k8serrors.NewGenericServerResponse(http.StatusUnprocessableEntity, "patch", groupResource, "fake-engine", "testing value /status/experiments/0/name failed: test failed", 0, false),
+			expectedAttempts: 2,
+		},
+		"Test Positive-3": {
+			err:              k8serrors.NewGenericServerResponse(http.StatusUnprocessableEntity, "patch", groupResource, "fake-engine", "testing value /status/experiments/0/name failed: test failed", 0, true),
+			expectedAttempts: 2,
+		},
+		"Test Negative-1": {
+			err: k8serrors.NewInvalid(v1alpha1.SchemeGroupVersion.WithKind("ChaosEngine").GroupKind(), "fake-engine", field.ErrorList{
+				field.NotSupported(field.NewPath("status", "experiments").Index(0).Child("verdict"), "Unknown", []string{"Pass", "Fail", "Awaited", "Stopped"}),
+			}),
+			expectedAttempts: 1,
+		},
+		"Test Negative-2": {
+			err:              k8serrors.NewNotFound(groupResource, "fake-engine"),
+			expectedAttempts: 1,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			err := retryOnStatusPatchFailure(func() error {
+				attempts++
+				if attempts == 1 {
+					return mock.err
+				}
+				return nil
+			})
+			if attempts != mock.expectedAttempts {
+				t.Fatalf("Test %q failed: expected %v attempts, got %v, err: %v", name, mock.expectedAttempts, attempts, err)
+			}
+		})
+	}
+}
+
+func TestSetStatusSubresource(t *testing.T) {
+	tests := map[string]struct {
+		resources                 []*metav1.APIResourceList
+		expectedStatusSubresource bool
+	}{
+		"Test Positive-1": {
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: v1alpha1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Name: "chaosengines"}, {Name: "chaosengines/status"}},
+				},
+			},
+			expectedStatusSubresource: true,
+		},
+		"Test Positive-2": {
+			expectedStatusSubresource: true,
+		},
+		"Test Negative-1": {
+			resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: v1alpha1.SchemeGroupVersion.String(),
+					APIResources: []metav1.APIResource{{Name: "chaosengines"}, {Name: "chaosresults/status"}},
+				},
+			},
+			expectedStatusSubresource: false,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := CreateFakeClient(t)
+			client.KubeClient.Discovery().(*fakediscovery.FakeDiscovery).Resources = mock.resources
+
+			var engineDetails EngineDetails
+			engineDetails.SetStatusSubresource(client)
+			if engineDetails.StatusSubresource != mock.expectedStatusSubresource {
+				t.Fatalf("Test %q failed: expected the status subresource to be %v, got %v", name, mock.expectedStatusSubresource, engineDetails.StatusSubresource)
+			}
+		})
+	}
+}
+
 func TestUpdateEngineWithResult(t *testing.T) {
 	fakeServiceAcc := "Fake Service Account"
 	fakeAppLabel := "Fake Label"
@@ -257,7 +468,7 @@ func TestUpdateEngineWithResult(t *testing.T) {
 			if err != nil {
 				t.Fatalf("chaosresult not created for %v test, err: %v", name, err)
 			}
-			err = engineDetails.UpdateEngineWithResult(&experiment, client)
+			err = engineDetails.UpdateEngineWithResult(context.Background(), &experiment, client)
 			if !mock.isErr && err != nil {
 				t.Fatalf("fail to update chaos engine with result for %v test, err: %v", name, err)
 			}
@@ -389,7 +600,7 @@ func TestDeleteJobAccordingToJobCleanUpPolicy(t *testing.T) {
 			if err != nil {
 				t.Fatalf("fail to create exp job pod for %v test, err: %v", name, err)
 			}
-			cleanupPolicy, err := engineDetails.DeleteJobAccordingToJobCleanUpPolicy(&experiment, client)
+			cleanupPolicy, err := engineDetails.DeleteJobAccordingToJobCleanUpPolicy(context.Background(), &experiment, client)
 			if err != nil {
 				t.Fatalf("fail to create exp job for %v test, err: %v", name, err)
 			}
@@ -405,6 +616,28 @@ func TestDeleteJobAccordingToJobCleanUpPolicy(t *testing.T) {
 	}
 }
 
+func TestGetChaosEngineWithDoneContext(t *testing.T) {
+	engineDetails := EngineDetails{
+		Name:            "Fake Engine",
+		EngineNamespace: "Fake NameSpace",
+	}
+	client := CreateFakeClient(t)
+	var attempts int
+	client.LitmusClient.(*litmusFakeClientset.Clientset).PrependReactor("get", "chaosengines", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		return true, nil, context.Canceled
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := engineDetails.GetChaosEngine(ctx, client); err == nil {
+		t.Fatalf("expected the chaosengine not to be returned once the context is done")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected the retries to stop once the context is done, got %v attempts", attempts)
+	}
+}
+
 func CreateFakeClient(t *testing.T) ClientSets {
 
 	clients := ClientSets{}
@@ -420,4 +653,32 @@ func (clients *ClientSets) SetFakeClient() {
 
 	// Load litmus client set by preloading with litmus objects.
 	clients.LitmusClient = litmusFakeClientset.NewSimpleClientset([]runtime.Object{}...)
+
+	// Emit the events through the fake kubernetes client set
+	clients.EventRecorder = NewEventRecorder(clients.KubeClient)
+}
+
+// notifyOnAction returns a channel, which receives the actions of the verb on the resource of the fake clientset,
+// so that the tests wait on the action instead of a fixed sleep
+// The actions are still served by the fake clientset, and the ones not received yet are dropped once the channel is full
+func notifyOnAction(fakeClient *k8stesting.Fake, verb, resource string) <-chan struct{} {
+	actions := make(chan struct{}, 16)
+	notify := func() {
+		select {
+		case actions <- struct{}{}:
+		default:
+		}
+	}
+	if verb == "watch" {
+		fakeClient.PrependWatchReactor(resource, func(k8stesting.Action) (bool, watch.Interface, error) {
+			notify()
+			return false, nil, nil
+		})
+		return actions
+	}
+	fakeClient.PrependReactor(verb, resource, func(k8stesting.Action) (bool, runtime.Object, error) {
+		notify()
+		return false, nil, nil
+	})
+	return actions
 }
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	"github.com/litmuschaos/chaos-runner/pkg/log"
+)
+
+// watchFallbackPollInterval is the interval of the polling, which the watches fall back to once they are forbidden
+const watchFallbackPollInterval = 2 * time.Second
+
+// errWatchForbidden is the cause of the cancellation of the watch, once its list or watch is forbidden
+var errWatchForbidden = errors.New("watch is forbidden")
+
+// objectWatch describes the single object waited for by waitUntil
+type objectWatch struct {
+	kind      string
+	name      string
+	namespace string
+	objType   runtime.Object
+	// list and watch are called with the field selector of the object name
+	list  func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error)
+	watch func(ctx context.Context, options metav1.ListOptions) (watch.Interface, error)
+	get   func(ctx context.Context) (runtime.Object, error)
+}
+
+// waitUntil waits till the condition is met by the object, watched through watchtools.UntilWithSync,
+// which lists and watches the object again once the watch is closed by the api server
+// It falls back to polling the object with get, once the list or watch is forbidden, as the service accounts
+// of the experiments may not grant those verbs. It returns false, if the context is done before the condition is met
+func waitUntil(ctx context.Context, object objectWatch, condition func(runtime.Object) bool) bool {
+	watchCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	selector := "metadata.name=" + object.name
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			list, err := object.list(watchCtx, options)
+			if k8serrors.IsForbidden(err) {
+				cancel(errWatchForbidden)
+			}
+			return list, err
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			watcher, err := object.watch(watchCtx, options)
+			if k8serrors.IsForbidden(err) {
+				cancel(errWatchForbidden)
+			}
+			return watcher, err
+		},
+	}
+	_, err := watchtools.UntilWithSync(watchCtx, lw, object.objType, nil, func(event watch.Event) (bool, error) {
+		if event.Type != watch.Added && event.Type != watch.Modified {
+			return false, nil
+		}
+		// the fake clientsets don't filter by the field selector, hence the name is checked again
+		accessor, err := meta.Accessor(event.Object)
+		if err != nil || accessor.GetName() != object.name {
+			return false, nil
+		}
+		return condition(event.Object), nil
+	})
+	if err == nil {
+		return true
+	}
+	if !errors.Is(context.Cause(watchCtx), errWatchForbidden) {
+		return false
+	}
+
+	log.Warnf("unable to watch %v name: %v, in namespace: %v, as the watch is forbidden, polling it every %v instead", object.kind, object.name, object.namespace, watchFallbackPollInterval)
+	for {
+		// the polling is kept quiet, as the failures are retried on the next interval
+		if obj, err := object.get(ctx); err == nil && condition(obj) {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(watchFallbackPollInterval):
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// WebhookURLKey is the key of the notifier secret holding the url of the webhook
+	WebhookURLKey = "url"
+	// WebhookSigningKey is the key of the notifier secret holding the HMAC key, which the payloads are signed with
+	WebhookSigningKey = "signingKey"
+	// WebhookRetriesKey is the key of the notifier secret holding the number of retries of a failed delivery
+	WebhookRetriesKey = "retries"
+	// WebhookTimeoutKey is the key of the notifier secret holding the timeout of each delivery, e.g. 5s
+	WebhookTimeoutKey = "timeout"
+	// DefaultWebhookRetries and DefaultWebhookTimeout are used unless provided inside the notifier secret
+	DefaultWebhookRetries = 3
+	DefaultWebhookTimeout = 10 * time.Second
+	// WebhookSignatureHeader is the header carrying the hex encoded HMAC-SHA256 of the payload, e.g. sha256=8f3a...
+	WebhookSignatureHeader = "X-Litmus-Signature"
+	// CloudEventsContentType is the content type of the structured CloudEvents
+	CloudEventsContentType = "application/cloudevents+json"
+	// defaultWebhookBackoff is the delay before the first retry, doubled after each retry
+	defaultWebhookBackoff = time.Second
+)
+
+// WebhookConfig contains the configuration of the webhook, derived from the notifier secret
+type WebhookConfig struct {
+	URL        string
+	SigningKey []byte
+	// Retries is the number of retries of a delivery, after the first attempt failed
+	Retries int
+	// Timeout bounds each attempt of a delivery
+	Timeout time.Duration
+	// Backoff is the delay before the first retry, defaults to 1s
+	Backoff time.Duration
+}
+
+// WebhookNotifier sends the notifications to an HTTP webhook, as CloudEvents 1.0 in the structured JSON mode
+type WebhookNotifier struct {
+	config WebhookConfig
+	client *http.Client
+}
+
+// CloudEvent is the CloudEvents 1.0 envelope of the notification
+type CloudEvent struct {
+	SpecVersion     string       `json:"specversion"`
+	ID              string       `json:"id"`
+	Source          string       `json:"source"`
+	Type            string       `json:"type"`
+	Subject         string       `json:"subject,omitempty"`
+	Time            string       `json:"time"`
+	DataContentType string       `json:"datacontenttype"`
+	Data            Notification `json:"data"`
+}
+
+// NewWebhookNotifier creates the webhook notifier from the given configuration
+func NewWebhookNotifier(config WebhookConfig) *WebhookNotifier {
+	if config.Backoff <= 0 {
+		config.Backoff = defaultWebhookBackoff
+	}
+	return &WebhookNotifier{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// newCloudEvent wraps the notification inside the CloudEvents envelope
+// The source is the chaosengine and the subject is the experiment, if any
+func newCloudEvent(notification Notification) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewString(),
+		Source:          "/apis/litmuschaos.io/v1alpha1/namespaces/" + notification.Namespace + "/chaosengines/" + notification.Engine,
+		Type:            string(notification.Type),
+		Subject:         notification.Experiment,
+		Time:            notification.Time.UTC().Format(time.RFC3339Nano),
+		DataContentType: "application/json",
+		Data:            notification,
+	}
+}
+
+// Notify delivers the notification to the webhook, retrying with an exponential backoff
+// The deliveries are retried on the connection failures, the 5xx and the 429 responses
+func (webhook *WebhookNotifier) Notify(ctx context.Context, notification Notification) error {
+	payload, err := json.Marshal(newCloudEvent(notification))
+	if err != nil {
+		return errors.Errorf("unable to marshal the notification, error: %v", err)
+	}
+	backoff := webhook.config.Backoff
+	for attempt := 0; ; attempt++ {
+		retriable, err := webhook.deliver(ctx, payload)
+		if err == nil {
+			return nil
+		}
+		if !retriable || attempt >= webhook.config.Retries {
+			return errors.Errorf("unable to deliver the notification to the webhook after %v attempts, error: %v", attempt+1, err)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Errorf("unable to deliver the notification to the webhook, error: %v", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// deliver sends the payload once, and returns whether the failed delivery can be retried
+func (webhook *WebhookNotifier) deliver(ctx context.Context, payload []byte) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", CloudEventsContentType)
+	if len(webhook.config.SigningKey) != 0 {
+		request.Header.Set(WebhookSignatureHeader, "sha256="+signPayload(webhook.config.SigningKey, payload))
+	}
+	response, err := webhook.client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+	retriable := response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests
+	return retriable, errors.Errorf("webhook responded with status: %v", response.Status)
+}
+
+// signPayload returns the hex encoded HMAC-SHA256 of the payload
+func signPayload(key, payload []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	notification := Notification{
+		Type:       NotificationCompleted,
+		Time:       metav1.Now(),
+		Engine:     "fake-engine",
+		Namespace:  "fake-namespace",
+		Experiment: "fake-exp-name",
+		Verdict:    "Pass",
+	}
+
+	tests := map[string]struct {
+		statuses         []int
+		retries          int
+		expectedAttempts int32
+		isErr            bool
+	}{
+		"Test Positive-1": {
+			statuses:         []int{http.StatusOK},
+			retries:          3,
+			expectedAttempts: 1,
+			isErr:            false,
+		},
+		"Test Positive-2": {
+			statuses:         []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusAccepted},
+			retries:          3,
+			expectedAttempts: 3,
+			isErr:            false,
+		},
+		"Test Negative-1": {
+			statuses:         []int{http.StatusBadRequest},
+			retries:          3,
+			expectedAttempts: 1,
+			isErr:            true,
+		},
+		"Test Negative-2": {
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			retries:          2,
+			expectedAttempts: 3,
+			isErr:            true,
+		},
+	}
+	for name, mock := range tests {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+				payload, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("unable to read the payload, err: %v", err)
+				}
+				if r.Header.Get("Content-Type") != CloudEventsContentType {
+					t.Errorf("expected the content type to be %v, got %v", CloudEventsContentType, r.Header.Get("Content-Type"))
+				}
+				if signature := r.Header.Get(WebhookSignatureHeader); signature != "sha256="+signPayload([]byte("fake-key"), payload) {
+					t.Errorf("expected the payload to be signed, got %v", signature)
+				}
+				var event CloudEvent
+				if err := json.Unmarshal(payload, &event); err != nil {
+					t.Errorf("unable to parse the cloudevent, err: %v", err)
+				}
+				if event.SpecVersion != "1.0" || event.Type != string(NotificationCompleted) || event.Subject != notification.Experiment ||
+					event.Source != "/apis/litmuschaos.io/v1alpha1/namespaces/fake-namespace/chaosengines/fake-engine" || event.Data.Verdict != "Pass" {
+					t.Errorf("unexpected cloudevent: %+v", event)
+				}
+				w.WriteHeader(mock.statuses[int(attempt)-1])
+			}))
+			defer server.Close()
+
+			webhook := NewWebhookNotifier(WebhookConfig{
+				URL:        server.URL,
+				SigningKey: []byte("fake-key"),
+				Retries:    mock.retries,
+				Timeout:    time.Second,
+				Backoff:    time.Millisecond,
+			})
+			err := webhook.Notify(context.Background(), notification)
+			if mock.isErr != (err != nil) {
+				t.Fatalf("Test %q failed: expected error is %v, got %v", name, mock.isErr, err)
+			}
+			if attempts != mock.expectedAttempts {
+				t.Fatalf("Test %q failed: expected %v attempts, got %v", name, mock.expectedAttempts, attempts)
+			}
+		})
+	}
+}